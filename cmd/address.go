@@ -1,28 +1,121 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/crypto/bls"
 	"github.com/zarbchain/zarb-wallet/wallet"
 )
 
-/// AllAddresses lists all the wallet addresses
+/// AllAddresses lists all the wallet addresses, by derivation order
 func AllAddresses() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		archivedOpt := c.Bool(cli.BoolOpt{
+			Name: "archived",
+			Desc: "show the archived addresses too",
+		})
+		roleOpt := c.String(cli.StringOpt{
+			Name: "role",
+			Desc: "show only the addresses with this role: account or validator",
+		})
+		methodOpt := c.String(cli.StringOpt{
+			Name: "method",
+			Desc: "show only the addresses created by this method: kdf-chain or imported",
+		})
+		nonZeroOpt := c.Bool(cli.BoolOpt{
+			Name: "non-zero",
+			Desc: "show only the addresses with balance or stake",
+		})
+		sortOpt := c.String(cli.StringOpt{
+			Name:  "sort",
+			Desc:  "sort the addresses by: index, label, balance or stake",
+			Value: "index",
+		})
+		pubOpt := c.Bool(cli.BoolOpt{
+			Name: "pubkey",
+			Desc: "show the public keys, the wallet password is needed if the wallet is encrypted",
+		})
+		offlineOpt := c.Bool(cli.BoolOpt{
+			Name: "offline",
+			Desc: "don't query the balances from the server",
+		})
+
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			if *nonZeroOpt && *offlineOpt {
+				exitWithError(withExitCode(exitInvalidInput,
+					errors.New("--non-zero needs the balances, it can't be used with --offline")))
+			}
+			less, ok := addressSorters[*sortOpt]
+			if !ok || (*offlineOpt && (*sortOpt == "balance" || *sortOpt == "stake")) {
+				exitWithError(withExitCode(exitInvalidInput, fmt.Errorf("invalid sort option: %s", *sortOpt)))
+			}
+			if *roleOpt != "" && !oneOf(*roleOpt, wallet.RoleAccount, wallet.RoleValidator) {
+				exitWithError(withExitCode(exitInvalidInput, fmt.Errorf("invalid role: %s", *roleOpt)))
+			}
+			if *methodOpt != "" && !oneOf(*methodOpt, "KDF-CHAIN", "IMPORTED") {
+				exitWithError(withExitCode(exitInvalidInput, fmt.Errorf("invalid method: %s", *methodOpt)))
+			}
+
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
-			PrintLine()
-			addrs := w.Addresses()
-			for addr, label := range addrs {
-				PrintInfoMsg("%s %s", addr, label)
+			items := []addressListItem{}
+			for _, info := range w.AddressInfos() {
+				if info.Archived && !*archivedOpt {
+					continue
+				}
+				if *roleOpt != "" && !strings.EqualFold(info.Role, *roleOpt) {
+					continue
+				}
+				if *methodOpt != "" && !strings.EqualFold(info.Method, *methodOpt) {
+					continue
+				}
+				items = append(items, addressListItem{AddressInfo: info})
+			}
+
+			if *pubOpt {
+				pubs, err := w.PublicKeys(getPassphrase(w))
+				if err != nil {
+					exitWithError(err)
+				}
+				for i := range items {
+					items[i].PublicKey = pubs[items[i].Address]
+				}
+			}
+
+			if !*offlineOpt {
+				addrs := make([]string, 0, len(items))
+				for _, item := range items {
+					addrs = append(addrs, item.Address)
+				}
+				balances, err := w.GetBalances(addrs)
+				if err != nil {
+					exitWithError(err)
+				}
+				filtered := items[:0]
+				for i, item := range items {
+					balance, stake := int64(balances[i].Balance), int64(balances[i].Stake)
+					if *nonZeroOpt && balance == 0 && stake == 0 {
+						continue
+					}
+					item.Balance, item.Stake = &balance, &stake
+					filtered = append(filtered, item)
+				}
+				items = filtered
 			}
+			sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+
+			PrintLine()
+			printAddressTable(items, *pubOpt, !*offlineOpt)
+			printResult(items)
 		}
 	}
 }
@@ -30,24 +123,25 @@ func AllAddresses() func(c *cli.Cmd) {
 /// NewAddress creates a new address
 func NewAddress() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		labelOpt := addLabelOption(c)
+
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			label := PromptInput("Label: ")
-			w, err := wallet.OpenWallet(*path)
+			label := labelOpt.get()
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			passphrase := getPassphrase(w)
 			addr, err := w.NewAddress(passphrase, label)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("%s", addr)
+			printResult(addressInfo{Address: addr, Label: label})
 		}
 	}
 }
@@ -60,21 +154,24 @@ func GetBalance() func(c *cli.Cmd) {
 			Desc: "address string",
 		})
 
-		c.Before = func() { fmt.Println(header) }
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			balance, stake, err := w.GetBalance(*addrArg)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
-			PrintInfoMsg("balance: %v, stake: %v", balance, stake)
+			PrintInfoMsg("balance: %s, stake: %s", balance.Format(*rawUnits), stake.Format(*rawUnits))
+			printResult(struct {
+				Address string `json:"address"`
+				Balance int64  `json:"balance"`
+				Stake   int64  `json:"stake"`
+			}{*addrArg, int64(balance), int64(stake)})
 		}
 	}
 }
@@ -87,23 +184,25 @@ func GetPrivateKey() func(c *cli.Cmd) {
 			Desc: "address string",
 		})
 
-		c.Before = func() { fmt.Println(header) }
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			passphrase := getPassphrase(w)
 			prv, err := w.PrivateKey(passphrase, *addrArg)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintWarnMsg("Private Key: \"%v\"", prv)
+			printResult(struct {
+				Address    string `json:"address"`
+				PrivateKey string `json:"private_key"`
+			}{*addrArg, prv})
 		}
 	}
 }
@@ -116,23 +215,90 @@ func GetPublicKey() func(c *cli.Cmd) {
 			Desc: "address string",
 		})
 
-		c.Before = func() { fmt.Println(header) }
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			passphrase := getPassphrase(w)
 			pub, err := w.PublicKey(passphrase, *addrArg)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("Public Key: \"%v\"", pub)
+			printResult(struct {
+				Address   string `json:"address"`
+				PublicKey string `json:"public_key"`
+			}{*addrArg, pub})
+		}
+	}
+}
+
+// SetLabel changes the label of an address
+func SetLabel() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address string",
+		})
+		labelArg := c.String(cli.StringArg{
+			Name: "LABEL",
+			Desc: "new label of the address",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			if err := w.SetLabel(*addrArg, *labelArg); err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintSuccessMsg("Label of %s is changed to %s", *addrArg, w.Label(*addrArg))
+			printResult(addressInfo{Address: *addrArg, Label: w.Label(*addrArg)})
+		}
+	}
+}
+
+// ArchiveAddress hides an address from the address list, its key is kept
+func ArchiveAddress(archive bool) func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address string",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			if archive {
+				err = w.Archive(*addrArg)
+			} else {
+				err = w.Unarchive(*addrArg)
+			}
+			if err != nil {
+				exitWithError(err)
+			}
+
+			if archive {
+				PrintSuccessMsg("Address %s is archived, it is still part of the wallet and can be unarchived", *addrArg)
+			} else {
+				PrintSuccessMsg("Address %s is unarchived", *addrArg)
+			}
+			printResult(addressInfo{Address: *addrArg, Label: w.Label(*addrArg), Archived: archive})
 		}
 	}
 }
@@ -140,25 +306,147 @@ func GetPublicKey() func(c *cli.Cmd) {
 // ImportPrivateKey imports a private key into the wallet
 func ImportPrivateKey() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		keyFileOpt := c.String(cli.StringOpt{
+			Name: "key-file",
+			Desc: "a path to a file containing the private key, or \"-\" to read it from stdin, e.g. --key-file=-",
+		})
+		labelOpt := addLabelOption(c)
+
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			prv := PromptInput("Private Key: ")
+			prv := readSecret(*keyFileOpt, "Private Key: ")
+			label := labelOpt.get()
 
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			passphrase := getPassphrase(w)
 			err = w.ImportPrivateKey(passphrase, prv)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
+			}
+			key, _ := bls.PrivateKeyFromString(prv)
+			addr := key.PublicKey().Address().String()
+			if label != "" {
+				if err := w.SetLabel(addr, label); err != nil {
+					exitWithError(err)
+				}
 			}
 
 			PrintLine()
 			PrintSuccessMsg("Private Key imported")
+			printResult(addressInfo{Address: addr, Label: w.Label(addr)})
 		}
 	}
 }
+
+// labelOption is the label of a new address, it is prompted if it is not set
+type labelOption struct {
+	label *string
+	set   *bool
+}
+
+func addLabelOption(c *cli.Cmd) labelOption {
+	opt := labelOption{set: new(bool)}
+	opt.label = c.String(cli.StringOpt{
+		Name:      "label",
+		Desc:      "label of the address, if not set it will be prompted",
+		SetByUser: opt.set,
+	})
+	return opt
+}
+
+func (opt labelOption) get() string {
+	if *opt.set {
+		return *opt.label
+	}
+	return PromptInput("Label: ")
+}
+
+type addressListItem struct {
+	wallet.AddressInfo
+	PublicKey string `json:"public_key,omitempty"`
+	Balance   *int64 `json:"balance,omitempty"`
+	Stake     *int64 `json:"stake,omitempty"`
+}
+
+// addressSorters are the orders of the address list, amounts are sorted from the largest
+var addressSorters = map[string]func(a, b addressListItem) bool{
+	"index":   func(a, b addressListItem) bool { return a.Index < b.Index },
+	"label":   func(a, b addressListItem) bool { return strings.ToLower(a.Label) < strings.ToLower(b.Label) },
+	"balance": func(a, b addressListItem) bool { return *a.Balance > *b.Balance },
+	"stake":   func(a, b addressListItem) bool { return *a.Stake > *b.Stake },
+}
+
+// oneOf checks if the option value is one of the values, case-insensitively
+func oneOf(opt string, values ...string) bool {
+	for _, v := range values {
+		if strings.EqualFold(opt, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// printAddressTable shows the address list as a table, with the total balance and stake
+func printAddressTable(items []addressListItem, showPub, showBalance bool) {
+	buf := new(strings.Builder)
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	row := func(cols ...string) { fmt.Fprintln(tw, strings.Join(cols, "\t")) }
+	headers := []string{"#", "ADDRESS", "LABEL", "METHOD", "ROLE"}
+	if showPub {
+		headers = append(headers, "PUBLIC KEY")
+	}
+	if showBalance {
+		headers = append(headers, "BALANCE", "STAKE")
+	}
+	row(headers...)
+
+	var totalBalance, totalStake int64
+	for _, item := range items {
+		label := item.Label
+		if item.Archived {
+			label += " (archived)"
+		}
+		cols := []string{fmt.Sprint(item.Index), item.Address, label, item.Method, item.Role}
+		if showPub {
+			cols = append(cols, item.PublicKey)
+		}
+		if showBalance {
+			cols = append(cols, formatAmount(*item.Balance), formatAmount(*item.Stake))
+			totalBalance += *item.Balance
+			totalStake += *item.Stake
+		}
+		row(cols...)
+	}
+	_ = tw.Flush()
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		PrintInfoMsg("%s", line)
+	}
+	PrintLine()
+	if showBalance {
+		PrintInfoMsg("%d addresses, total balance: %s, total stake: %s",
+			len(items), formatAmount(totalBalance), formatAmount(totalStake))
+	} else {
+		PrintInfoMsg("%d addresses", len(items))
+	}
+}
+
+type addressInfo struct {
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Archived bool   `json:"archived,omitempty"`
+}
+
+// makeAddressList converts the addresses to a list, sorted by address
+func makeAddressList(w *wallet.Wallet, addrs map[string]string) []addressInfo {
+	list := make([]addressInfo, 0, len(addrs))
+	for addr, label := range addrs {
+		list = append(list, addressInfo{Address: addr, Label: label, Archived: w.IsArchived(addr)})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Address < list[j].Address })
+	return list
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// AddContact adds an external address to the address book
+func AddContact() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Spec = "[--note] [--tag...] NAME ADDR"
+		nameArg := c.String(cli.StringArg{
+			Name: "NAME",
+			Desc: "name of the contact, it can be used instead of the address",
+		})
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address of the contact",
+		})
+		noteOpt := c.String(cli.StringOpt{
+			Name: "note",
+			Desc: "a note about the contact",
+		})
+		tagOpt := c.Strings(cli.StringsOpt{
+			Name: "tag",
+			Desc: "a tag of the contact, it can be repeated",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			contact := wallet.Contact{
+				Name:    *nameArg,
+				Address: *addrArg,
+				Note:    *noteOpt,
+				Tags:    *tagOpt,
+			}
+			if err := w.AddContact(contact); err != nil {
+				exitWithError(err)
+			}
+			contact, _ = w.Contact(strings.TrimSpace(*nameArg))
+
+			PrintLine()
+			if w.Contains(contact.Address) {
+				PrintWarnMsg("%s is an address of this wallet", contact.Address)
+			}
+			PrintSuccessMsg("Contact %s is added: %s", contact.Name, contact.Address)
+			printResult(contact)
+		}
+	}
+}
+
+// ListContacts shows the contacts of the address book
+func ListContacts() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		tagOpt := c.String(cli.StringOpt{
+			Name: "tag",
+			Desc: "show only the contacts with this tag",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			contacts := []wallet.Contact{}
+			for _, contact := range w.Contacts() {
+				if *tagOpt != "" && !contact.HasTag(*tagOpt) {
+					continue
+				}
+				line := contact.Name + " " + contact.Address
+				if len(contact.Tags) > 0 {
+					line += " [" + strings.Join(contact.Tags, ", ") + "]"
+				}
+				if contact.Note != "" {
+					line += " " + contact.Note
+				}
+				PrintInfoMsg("%s", line)
+				contacts = append(contacts, contact)
+			}
+			printResult(contacts)
+		}
+	}
+}
+
+// RemoveContact removes a contact from the address book
+func RemoveContact() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		nameArg := c.String(cli.StringArg{
+			Name: "NAME",
+			Desc: "name of the contact",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			contact, ok := w.Contact(*nameArg)
+			if err := w.RemoveContact(*nameArg); err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintSuccessMsg("Contact %s is removed", *nameArg)
+			if ok {
+				printResult(contact)
+			}
+		}
+	}
+}
+
+// resolveReceiver returns the address of the receiver, that can be a contact name
+func resolveReceiver(w *wallet.Wallet, nameOrAddr string) string {
+	addr, err := w.ResolveAddress(nameOrAddr)
+	if err != nil {
+		exitWithError(err)
+	}
+	return addr
+}
+
+// printReceiver shows the receiver in the confirmation of a transaction,
+// and warns if it is not a contact or an address of this wallet
+func printReceiver(w *wallet.Wallet, title, addr string) {
+	a, _ := crypto.AddressFromString(addr)
+	PrintInfoMsg("%s: %s", title, addressWithLabel(w, a))
+	if _, ok := w.ContactByAddress(addr); !ok && !w.Contains(addr) {
+		PrintWarnMsg("The receiver is not in your contacts, make sure the address is correct")
+	}
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	cli "github.com/jawher/mow.cli"
 	"github.com/zarbchain/zarb-wallet/wallet"
 )
@@ -10,25 +8,51 @@ import (
 // Generate creates a new wallet
 func Generate() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		networkOpt := addNetworkOption(c)
+
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			passphrase := PromptPassphrase("Passphrase: ", true)
-			w, err := wallet.CreateWallet(*path, passphrase, 0)
+			net := parseNetwork(*networkOpt)
+			passphrase := getNewPassphrase()
+			w, err := wallet.CreateWallet(*path, passphrase, int(net))
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			mnemonic, err := w.Mnemonic(passphrase)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintSuccessMsg("Wallet created successfully at: %s", w.Path())
+			PrintInfoMsg("Network: %s", w.Network())
 			PrintInfoMsg("Seed: \"%v\"", mnemonic)
 			PrintWarnMsg("Please keep your seed in a safe place; if you lose it, you will not be able to restore your wallet.")
+			printResult(walletInfo{Path: w.Path(), Network: w.Network().String(), Seed: mnemonic})
 		}
 	}
 }
+
+type walletInfo struct {
+	Path    string `json:"path"`
+	Network string `json:"network,omitempty"`
+	Seed    string `json:"seed,omitempty"`
+}
+
+// addNetworkOption adds the option that sets the network of a new wallet
+func addNetworkOption(c *cli.Cmd) *string {
+	return c.String(cli.StringOpt{
+		Name:  "network",
+		Desc:  "network of the wallet: mainnet, testnet or localnet",
+		Value: wallet.Mainnet.String(),
+	})
+}
+
+func parseNetwork(name string) wallet.Network {
+	net, err := wallet.ParseNetwork(name)
+	if err != nil {
+		exitWithError(withExitCode(exitInvalidInput, err))
+	}
+	return net
+}
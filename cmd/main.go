@@ -4,22 +4,69 @@ import (
 	"os"
 
 	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-wallet/wallet"
 )
 
 var path *string
+var pathSetByUser bool
+var rawUnits *bool
+var output *string
+var passwordFile *string
+var assumeYes *bool
 
 func main() {
 	app := cli.App("zarb-wallet", "Zarb wallet")
 
 	path = app.String(cli.StringOpt{
-		Name:  "w wallet file",
-		Desc:  "a path to the wallet file",
-		Value: ZarbWalletsDir() + "default_wallet",
+		Name:      "w wallet file",
+		Desc:      "a path to the wallet file, by default it is the current wallet in " + ZarbWalletsDir() + " (see \"wallet use\")",
+		SetByUser: &pathSetByUser,
 	})
 
+	rawUnits = app.Bool(cli.BoolOpt{
+		Name:  "raw-units",
+		Desc:  "amounts are given and shown in base units, instead of ZRB",
+		Value: false,
+	})
+
+	output = app.String(cli.StringOpt{
+		Name:  "o output",
+		Desc:  "output format: text or json. In JSON format amounts are in base units and the messages are written to stderr",
+		Value: outputText,
+	})
+
+	passwordFile = app.String(cli.StringOpt{
+		Name: "password-file",
+		Desc: "a path to a file containing the wallet password, or \"-\" to read it from stdin, e.g. --password-file=-. " +
+			"The password can also be set by " + passwordEnv + " environment variable",
+	})
+
+	assumeYes = app.Bool(cli.BoolOpt{
+		Name:  "y yes",
+		Desc:  "don't ask for confirmation, for non-interactive use",
+		Value: false,
+	})
+
+	app.Before = func() {
+		setOutput()
+		if !pathSetByUser {
+			*path = walletManager().CurrentPath()
+		}
+	}
+
 	app.Command("create", "Create a new wallet", Generate())
 	app.Command("recover", "Recover waller from the seed phrase (mnemonic)", Recover())
 	app.Command("seed", "Show secret seed phrase (mnemonic) that can be used to recover this wallet", GetSeed())
+	app.Command("sign-message", "Sign a message with the private key of an address", SignMessage())
+	app.Command("verify-message", "Verify the signature of a message", VerifyMessage())
+	app.Command("serve", "Run a daemon serving the wallet over JSON-RPC", Serve())
+	app.Command("wallet", "Manage the wallets", func(k *cli.Cmd) {
+		k.Command("list", "Show all wallets in the wallets directory", ListWallets())
+		k.Command("use", "Set the current wallet", UseWallet())
+		k.Command("rename", "Rename a wallet", RenameWallet())
+		k.Command("delete", "Delete a wallet", DeleteWallet())
+		k.Command("sweep-imported", "Move the balance of all imported addresses into a new address", SweepImported())
+	})
 	app.Command("address", "Manage address book", func(k *cli.Cmd) {
 		k.Command("new", "Creating a new address", NewAddress())
 		k.Command("all", "Show all addresses", AllAddresses())
@@ -27,15 +74,68 @@ func main() {
 		k.Command("pubkey", "Get public key of an address", GetPublicKey())
 		k.Command("privkey", "Get private key of an address", GetPrivateKey())
 		k.Command("import", "Import a private key into wallet", ImportPrivateKey())
+		k.Command("label", "Change the label of an address", SetLabel())
+		k.Command("archive", "Hide an address from the address list, its key is kept", ArchiveAddress(true))
+		k.Command("unarchive", "Show an archived address in the address list again", ArchiveAddress(false))
+	})
+	app.Command("contact", "Manage the address book of external contacts", func(k *cli.Cmd) {
+		k.Command("add", "Add a contact", AddContact())
+		k.Command("list", "Show all contacts", ListContacts())
+		k.Command("remove", "Remove a contact", RemoveContact())
+	})
+	app.Command("validator", "Manage validator keys", func(k *cli.Cmd) {
+		k.Command("new", "Create a new validator key", NewValidator())
+		k.Command("list", "Show all validator addresses", ListValidators())
+		k.Command("status", "Show the state of a validator in the blockchain", ValidatorStatus())
+		k.Command("export-key", "Export the validator private key for the node", ExportValidatorKey())
+	})
+	app.Command("multisig", "Manage multisig accounts", func(k *cli.Cmd) {
+		k.Command("member", "Show the member information of an address, to share with the other members", MultisigMember())
+		k.Command("new", "Create a multisig account from the members' information", NewMultisig())
+		k.Command("list", "Show all multisig accounts", ListMultisig())
+		k.Command("send", "Create a partial send transaction for a multisig account", MultisigSendTx())
+		k.Command("sign", "Sign a partial transaction as a member", MultisigSign())
+		k.Command("combine", "Aggregate the members' signatures and publish the transaction", MultisigCombine())
+	})
+	app.Command("stake", "Move the stake of a validator", func(k *cli.Cmd) {
+		k.Command("move", "Unbond a validator, withdraw its stake and optionally bond it to another validator", MoveStake())
+		k.Command("status", "Show the progress of moving the stake of a validator", StakeStatus())
 	})
 	app.Command("tx", "Create, sign and publish a transaction", func(k *cli.Cmd) {
 		k.Command("bond", "Create, sign and publish a bond transaction", BondTx())
 		k.Command("send", "Create, sign and publish a send transaction", SendTx())
 		k.Command("unbond", "Create, sign and publish an unbond transaction", UnbondTx())
 		k.Command("withdraw", "Create, sign and publish a withdraw transaction", WithdrawTx())
+		k.Command("sortition", "Create, sign and publish a sortition transaction", SortitionTx())
+		k.Command("batch", "Sign and publish send transactions from a CSV or JSON file", BatchTx())
+		k.Command("resubmit", "Rebuild a pending transaction with a new stamp and publish it again", ResubmitTx())
+		k.Command("pending", "Show the transactions that are not committed yet", TxPending())
+		k.Command("status", "Show the status of a transaction", TxStatus())
+		k.Command("show", "Show the details of a transaction", TxShow())
+		k.Command("decode", "Decode and verify a raw transaction", TxDecode())
 	})
 
 	if err := app.Run(os.Args); err != nil {
 		panic(err)
 	}
 }
+
+// walletManager returns the manager of the wallets directory
+func walletManager() *wallet.Manager {
+	return wallet.NewManager(ZarbWalletsDir())
+}
+
+// openWallet opens the wallet and applies the global options
+func openWallet() (*wallet.Wallet, error) {
+	w, err := wallet.OpenWallet(*path)
+	if err != nil {
+		return nil, withExitCode(exitWallet, err)
+	}
+	w.SetRawUnits(*rawUnits)
+	return w, nil
+}
+
+// formatAmount formats an amount in base units, based on the global options
+func formatAmount(amount int64) string {
+	return wallet.Amount(amount).Format(*rawUnits)
+}
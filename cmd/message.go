@@ -0,0 +1,91 @@
+package main
+
+import (
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// SignMessage signs a message with the private key of an address, to prove its ownership
+func SignMessage() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address string",
+		})
+		msgArg := c.String(cli.StringArg{
+			Name: "MESSAGE",
+			Desc: "message to be signed",
+		})
+		jsonOpt := c.Bool(cli.BoolOpt{
+			Name:  "json",
+			Desc:  "print the signed message in JSON format, the same as \"--output json\"",
+			Value: false,
+		})
+
+		c.Before = func() {
+			if *jsonOpt {
+				useJSONOutput()
+			}
+			printHeader(header)
+		}
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			passphrase := getPassphrase(w)
+			signed, err := w.SignMessage(passphrase, *addrArg, *msgArg)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			printResult(signed)
+			PrintInfoMsg("Address: %s", signed.Address)
+			PrintInfoMsg("Public key: %s", signed.PublicKey)
+			PrintInfoMsg("Signature: %s", signed.Proof())
+		}
+	}
+}
+
+// VerifyMessage verifies the signature of a message for an address
+func VerifyMessage() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address string",
+		})
+		sigArg := c.String(cli.StringArg{
+			Name: "SIG",
+			Desc: "signature as \"public_key:signature\", or the signed message in JSON format, or a path to a file containing it",
+		})
+		msgArg := c.String(cli.StringArg{
+			Name: "MESSAGE",
+			Desc: "signed message",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			sig := *sigArg
+			if util.PathExists(sig) {
+				data, err := util.ReadFile(sig)
+				if err != nil {
+					exitWithError(err)
+				}
+				sig = string(data)
+			}
+
+			PrintLine()
+			if err := wallet.VerifyMessage(*addrArg, sig, *msgArg); err != nil {
+				exitWithError(err)
+			}
+			PrintSuccessMsg("Signature is valid")
+			printResult(struct {
+				Address string `json:"address"`
+				Valid   bool   `json:"valid"`
+			}{*addrArg, true})
+		}
+	}
+}
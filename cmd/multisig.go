@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/cmd"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// MultisigMember shows the member information of an address, to be shared with the other members
+func MultisigMember() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address of the member in this wallet",
+		})
+		outOpt := c.String(cli.StringOpt{
+			Name: "out",
+			Desc: "write the member information into the file",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			passphrase := getPassphrase(w)
+			member, err := w.MultisigMember(passphrase, *addrArg)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			data, _ := json.MarshalIndent(member, "", "  ")
+			if *outOpt != "" {
+				if err := util.WriteFile(*outOpt, data); err != nil {
+					exitWithError(err)
+				}
+				PrintSuccessMsg("Member information is written to %s", *outOpt)
+			} else if !jsonOutput() {
+				PrintInfoMsg("%s", data)
+			}
+			printResult(member)
+		}
+	}
+}
+
+// NewMultisig creates a multisig account from the member files
+func NewMultisig() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Spec = "[--label] THRESHOLD MEMBERS..."
+		labelOpt := c.String(cli.StringOpt{
+			Name: "label",
+			Desc: "label of the account",
+		})
+		thresholdArg := c.Int(cli.IntArg{
+			Name: "THRESHOLD",
+			Desc: "number of the members required to sign, it should be equal to the number of members",
+		})
+		membersArg := c.Strings(cli.StringsArg{
+			Name: "MEMBERS",
+			Desc: "files containing the member information, created by \"multisig member\"",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			members := []wallet.MultisigMember{}
+			for _, file := range *membersArg {
+				data, err := util.ReadFile(file)
+				if err != nil {
+					exitWithError(err)
+				}
+				m := wallet.MultisigMember{}
+				if err := json.Unmarshal(data, &m); err != nil {
+					exitWithError(withExitCode(exitInvalidInput, fmt.Errorf("%s: %w", file, err)))
+				}
+				members = append(members, m)
+			}
+
+			addr, err := w.NewMultisigAccount(*labelOpt, *thresholdArg, members)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("%s", addr)
+			account, _ := w.MultisigAccount(addr)
+			printResult(account)
+		}
+	}
+}
+
+// ListMultisig lists the multisig accounts of the wallet
+func ListMultisig() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			accounts := []wallet.MultisigAccount{}
+			for _, a := range w.MultisigAccounts() {
+				PrintInfoMsg("%s %s (%d-of-%d)", a.Address, a.Label, a.Threshold, len(a.Members))
+				accounts = append(accounts, a)
+			}
+			printResult(accounts)
+		}
+	}
+}
+
+// MultisigSendTx creates a partial send transaction for a multisig account
+func MultisigSendTx() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		fromArg := c.String(cli.StringArg{
+			Name: "FROM",
+			Desc: "multisig account address",
+		})
+		toArg := c.String(cli.StringArg{
+			Name: "TO",
+			Desc: "receiver address, or the name of a contact",
+		})
+		amountArg := c.String(cli.StringArg{
+			Name: "AMOUNT",
+			Desc: "the amount to be transferred, in ZRB (e.g. 1.5)",
+		})
+		outArg := c.String(cli.StringArg{
+			Name: "FILE",
+			Desc: "partial transaction file, to be signed by the members",
+		})
+		opts := addCommonTxOptions(c)
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWalletForTx(opts)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			to := resolveReceiver(w, *toArg)
+			trx, err := w.MakeSendTx(*opts.stamp, *opts.seq, *fromArg, to, *amountArg, *opts.fee, *opts.memo, *opts.force)
+			if err != nil {
+				exitWithError(err)
+			}
+			partial, err := w.NewPartialTx(trx)
+			if err != nil {
+				exitWithError(err)
+			}
+			if err := partial.Save(*outArg); err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			printTx(w, trx)
+			PrintSuccessMsg("Partial transaction is written to %s, send it to the members to sign", *outArg)
+			printPartialTxResult(w, trx, partial, *outArg, nil)
+		}
+	}
+}
+
+// MultisigSign signs a partial transaction with a member key of this wallet
+func MultisigSign() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		fileArg := c.String(cli.StringArg{
+			Name: "FILE",
+			Desc: "partial transaction file",
+		})
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "address of the member in this wallet",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+			partial, err := wallet.ReadPartialTx(*fileArg)
+			if err != nil {
+				exitWithError(err)
+			}
+			trx, err := partial.Transaction()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to sign this transaction for multisig account %s:", partial.Account)
+			printTx(w, trx)
+			if !PromptConfirm("Do you want to continue? ") {
+				exitWithError(errCanceled)
+			}
+
+			passphrase := getPassphrase(w)
+			if err := w.SignPartialTx(passphrase, partial, *addrArg); err != nil {
+				exitWithError(err)
+			}
+			if err := partial.Save(*fileArg); err != nil {
+				exitWithError(err)
+			}
+			missing := printMissingSigners(w, partial)
+			printPartialTxResult(w, trx, partial, *fileArg, missing)
+		}
+	}
+}
+
+// MultisigCombine aggregates the signatures of a partial transaction and publishes it
+func MultisigCombine() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		fileArg := c.String(cli.StringArg{
+			Name: "FILE",
+			Desc: "partial transaction file, signed by all the members",
+		})
+		dryRunOpt := c.Bool(cli.BoolOpt{
+			Name:  "dry-run",
+			Desc:  "show the signed transaction, without broadcasting it",
+			Value: false,
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+			partial, err := wallet.ReadPartialTx(*fileArg)
+			if err != nil {
+				exitWithError(err)
+			}
+			trx, err := w.CombinePartialTx(partial)
+			if err != nil {
+				if errors.Is(err, wallet.ErrMissingSignatures) {
+					printMissingSigners(w, partial)
+				}
+				exitWithError(err)
+			}
+
+			PrintLine()
+			printTx(w, trx)
+			if *dryRunOpt {
+				bs, _ := trx.Bytes()
+				PrintInfoMsg("Signed transaction: %x", bs)
+				printTxResult(w, trx, false)
+				return
+			}
+
+			PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
+			if !PromptConfirm("Do you want to continue? ") {
+				exitWithError(errCanceled)
+			}
+			res, err := w.Broadcast(trx)
+			if err != nil {
+				exitWithError(err)
+			}
+			PrintInfoMsg(res)
+			printTxResult(w, trx, true)
+		}
+	}
+}
+
+func printMissingSigners(w *wallet.Wallet, partial *wallet.PartialTx) []string {
+	missing, err := w.MissingSigners(partial)
+	if err != nil {
+		exitWithError(err)
+	}
+	if len(missing) == 0 {
+		PrintSuccessMsg("All the members have signed, the transaction can be combined")
+		return missing
+	}
+	PrintInfoMsg("Waiting for the signatures of:")
+	for _, pub := range missing {
+		PrintInfoMsg("  %s", pub)
+	}
+	return missing
+}
+
+type partialTxResult struct {
+	File           string   `json:"file"`
+	Account        string   `json:"account"`
+	Tx             *txInfo  `json:"tx"`
+	Signatures     int      `json:"signatures"`
+	MissingSigners []string `json:"missing_signers"`
+}
+
+func printPartialTxResult(w *wallet.Wallet, trx *tx.Tx, partial *wallet.PartialTx, file string, missing []string) {
+	if missing == nil {
+		missing, _ = w.MissingSigners(partial)
+	}
+	printResult(partialTxResult{
+		File:           file,
+		Account:        partial.Account,
+		Tx:             makeTxInfo(w, trx),
+		Signatures:     len(partial.Signatures),
+		MissingSigners: missing,
+	})
+}
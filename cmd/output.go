@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-wallet/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Output formats of the commands
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Exit codes of the commands, so the scripts can tell the error classes apart
+const (
+	exitFailure      = 1 // Unclassified errors
+	exitInvalidInput = 2 // Invalid arguments or input files, mow.cli uses it for usage errors too
+	exitWallet       = 3 // The wallet can't be opened or decrypted
+	exitNotFound     = 4 // The address, account, validator or transaction doesn't exist
+	exitRejected     = 5 // The transaction is rejected by the blockchain rules
+	exitNetwork      = 6 // The node is unreachable
+	exitCanceled     = 7 // The operation is canceled by the user
+	exitSignature    = 8 // The signature is invalid or missing
+)
+
+var errorClasses = map[int]string{
+	exitFailure:      "failure",
+	exitInvalidInput: "invalid_input",
+	exitWallet:       "wallet",
+	exitNotFound:     "not_found",
+	exitRejected:     "rejected",
+	exitNetwork:      "network",
+	exitCanceled:     "canceled",
+	exitSignature:    "signature",
+}
+
+// errCanceled is returned when the user doesn't confirm the operation
+var errCanceled = errors.New("canceled by the user")
+
+var walletErrorCodes = []struct {
+	err  error
+	code int
+}{
+	{wallet.ErrWalletExits, exitWallet},
+	{wallet.ErrInvalidCRC, exitWallet},
+	{wallet.ErrInvalidNetwork, exitWallet},
+	{wallet.ErrInvalidPassphrase, exitWallet},
+	{wallet.ErrChainMismatch, exitNetwork},
+	{wallet.ErrWalletNotFound, exitNotFound},
+	{wallet.ErrAddressNotFound, exitNotFound},
+	{wallet.ErrAccountNotFound, exitNotFound},
+	{wallet.ErrValidatorNotFound, exitNotFound},
+	{wallet.ErrTxNotFound, exitNotFound},
+	{wallet.ErrTxNotPending, exitNotFound},
+	{wallet.ErrContactNotFound, exitNotFound},
+	{wallet.ErrInsufficientFunds, exitRejected},
+	{wallet.ErrInvalidSequence, exitRejected},
+	{wallet.ErrInvalidFee, exitRejected},
+	{wallet.ErrValidatorUnbonded, exitRejected},
+	{wallet.ErrValidatorNotUnbonded, exitRejected},
+	{wallet.ErrValidatorNotBonded, exitRejected},
+	{wallet.ErrTxConfirmed, exitRejected},
+	{wallet.ErrInvalidWalletName, exitInvalidInput},
+	{wallet.ErrInvalidAmount, exitInvalidInput},
+	{wallet.ErrInvalidReceiver, exitInvalidInput},
+	{wallet.ErrMemoTooLong, exitInvalidInput},
+	{wallet.ErrAddressExists, exitInvalidInput},
+	{wallet.ErrContactExists, exitInvalidInput},
+	{wallet.ErrNotValidatorAddress, exitInvalidInput},
+	{wallet.ErrThresholdNotSupported, exitInvalidInput},
+	{wallet.ErrInvalidMember, exitInvalidInput},
+	{wallet.ErrInvalidSignature, exitSignature},
+	{wallet.ErrMissingSignatures, exitSignature},
+	{errCanceled, exitCanceled},
+}
+
+// codedError is an error with a known exit code
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode sets the exit code of the error
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// exitCode returns the exit code of the error
+func exitCode(err error) int {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	for _, c := range walletErrorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return exitNetwork
+		case codes.NotFound:
+			return exitNotFound
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			return exitRejected
+		}
+	}
+	return exitFailure
+}
+
+// textOut is where the human readable messages are written.
+// With JSON output they go to stderr, so stdout has only the JSON result.
+var textOut io.Writer = os.Stdout
+
+// jsonOutput returns true if the result should be printed in JSON format
+func jsonOutput() bool {
+	return *output == outputJSON
+}
+
+// setOutput checks the output format and applies it
+func setOutput() {
+	switch *output {
+	case outputText:
+	case outputJSON:
+		textOut = os.Stderr
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid output format: %s, expected text or json\n", *output)
+		cli.Exit(exitInvalidInput)
+	}
+}
+
+// useJSONOutput switches to JSON output, for the commands that have their own --json option
+func useJSONOutput() {
+	*output = outputJSON
+	setOutput()
+}
+
+func printHeader(header string) {
+	fmt.Fprintln(textOut, header)
+}
+
+// printResult prints the result of the command in JSON format, if it is requested.
+// With text output, the commands print their own messages.
+func printResult(obj interface{}) {
+	if !jsonOutput() {
+		return
+	}
+	data, err := json.MarshalIndent(obj, "", "   ")
+	if err != nil {
+		exitWithError(err)
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+}
+
+type errorResult struct {
+	Error struct {
+		Class   string `json:"class"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// exitWithError prints the error and exits with the exit code of its class
+func exitWithError(err error) {
+	code := exitCode(err)
+	if jsonOutput() {
+		res := errorResult{}
+		res.Error.Class = errorClasses[code]
+		res.Error.Code = code
+		res.Error.Message = err.Error()
+		data, _ := json.MarshalIndent(res, "", "   ")
+		fmt.Fprintln(os.Stdout, string(data))
+	} else {
+		PrintDangerMsg("%s", err.Error())
+	}
+	cli.Exit(code)
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	cli "github.com/jawher/mow.cli"
 	"github.com/zarbchain/zarb-wallet/wallet"
 )
@@ -10,18 +8,39 @@ import (
 /// Recover recovers a wallet from mnemonic (seed phrase)
 func Recover() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		seedFileOpt := c.String(cli.StringOpt{
+			Name: "seed-file",
+			Desc: "a path to a file containing the seed phrase, or \"-\" to read it from stdin, e.g. --seed-file=-",
+		})
+		networkOpt := addNetworkOption(c)
+		legacyOpt := c.Bool(cli.BoolOpt{
+			Name: "legacy-keys",
+			Desc: "recover the keys of a testnet or localnet wallet created by the versions before the network-separated keys",
+		})
+
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			mnemonic := PromptInput("Seed: ")
-			w, err := wallet.RecoverWallet(*path, mnemonic, 0)
+			net := parseNetwork(*networkOpt)
+			mnemonic := readSecret(*seedFileOpt, "Seed: ")
+			recoverWallet := wallet.RecoverWallet
+			if *legacyOpt {
+				recoverWallet = wallet.RecoverLegacyWallet
+			}
+			w, err := recoverWallet(*path, mnemonic, int(net))
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("Wallet recovered successfully at: %s", w.Path())
+			PrintInfoMsg("Network: %s", w.Network())
+			if net != wallet.Mainnet && !*legacyOpt {
+				PrintWarnMsg("The %s wallets created by the older versions use other keys. "+
+					"If the addresses of this wallet are not the expected ones, "+
+					"recover it again with --legacy-keys.", net)
+			}
 			PrintWarnMsg("Never share your private key.")
+			printResult(walletInfo{Path: w.Path(), Network: w.Network().String()})
 		}
 	}
 }
@@ -29,19 +48,22 @@ func Recover() func(c *cli.Cmd) {
 /// GetSeed prints the seed phrase (mnemonics)
 func GetSeed() func(c *cli.Cmd) {
 	return func(c *cli.Cmd) {
-		c.Before = func() { fmt.Println(header) }
+		c.Before = func() { printHeader(header) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWallet()
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			passphrase := getPassphrase(w)
 			mnemonic, err := w.Mnemonic(passphrase)
+			if err != nil {
+				exitWithError(err)
+			}
 
 			PrintLine()
 			PrintInfoMsg("Seed: \"%v\"", mnemonic)
+			printResult(walletInfo{Path: w.Path(), Seed: mnemonic})
 		}
 	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// The secrets (the wallet password, the seed and the private keys) are read from
+// the terminal, or from files and the environment for non-interactive use.
+// They are never taken as command-line arguments, since the arguments are
+// visible to the other users of the system and are kept in the shell history.
+
+// passwordEnv is the environment variable that holds the wallet password
+const passwordEnv = "ZARB_WALLET_PASSWORD"
+
+// errStdinUsed is returned when more than one secret is read from stdin.
+// The first secret consumes stdin, so the next ones would be empty.
+var errStdinUsed = errors.New("only one secret can be read from stdin (\"-\") in a command")
+
+// stdinRead is set when a secret is read from stdin
+var stdinRead = false
+
+// readSecretFile reads a secret from the file, or from stdin if the path is "-".
+// The trailing line break is removed.
+func readSecretFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		if stdinRead {
+			return "", errStdinUsed
+		}
+		stdinRead = true
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = util.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// readPassword reads the wallet password from the password file or the environment.
+// It returns false if none of them is set.
+func readPassword() (string, bool) {
+	if *passwordFile != "" {
+		password, err := readSecretFile(*passwordFile)
+		if err != nil {
+			exitWithError(withExitCode(exitInvalidInput, fmt.Errorf("failed to read the password file: %w", err)))
+		}
+		return password, true
+	}
+	return os.LookupEnv(passwordEnv)
+}
+
+// getPassphrase returns the password of the wallet, if it is encrypted
+func getPassphrase(w *wallet.Wallet) string {
+	passphrase := ""
+	if w.IsEncrypted() {
+		password, ok := readPassword()
+		if ok {
+			return password
+		}
+		passphrase = PromptPassphrase("Wallet password: ", false)
+	}
+	return passphrase
+}
+
+// getNewPassphrase returns the password of a new wallet
+func getNewPassphrase() string {
+	password, ok := readPassword()
+	if ok {
+		return password
+	}
+	return PromptPassphrase("Passphrase: ", true)
+}
+
+// readSecret reads a secret from the file, or prompts for it if the path is not set
+func readSecret(path, prompt string) string {
+	if path == "" {
+		return PromptInput(prompt)
+	}
+	if path == "-" && *passwordFile == "-" {
+		exitWithError(withExitCode(exitInvalidInput, errStdinUsed))
+	}
+	secret, err := readSecretFile(path)
+	if err != nil {
+		exitWithError(withExitCode(exitInvalidInput, err))
+	}
+	return strings.TrimSpace(secret)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-wallet/wallet"
+	"github.com/zarbchain/zarb-wallet/www/grpc"
+	"github.com/zarbchain/zarb-wallet/www/jsonrpc"
+)
+
+// Serve runs a daemon that serves the wallet operations over JSON-RPC 2.0
+func Serve() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		listenOpt := c.String(cli.StringOpt{
+			Name:  "listen",
+			Desc:  "a loopback address, or a path to a Unix socket (e.g. ./wallet.sock)",
+			Value: "127.0.0.1:9901",
+		})
+		grpcOpt := c.String(cli.StringOpt{
+			Name: "grpc",
+			Desc: "a loopback address, or a path to a Unix socket, to serve the zarb.wallet.v1 gRPC service too",
+		})
+		tokensOpt := c.String(cli.StringOpt{
+			Name: "tokens",
+			Desc: "a path to the token file, by default it is next to the wallet file. " +
+				"If it doesn't exist, it is created with a token that can call all the methods",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			tokensPath := *tokensOpt
+			if tokensPath == "" {
+				tokensPath = wallet.TokensPath(w.Path())
+			}
+			tokens, created, err := jsonrpc.LoadTokens(tokensPath)
+			if err != nil {
+				exitWithError(withExitCode(exitInvalidInput, err))
+			}
+			if created {
+				PrintWarnMsg("A token that can call all the methods is written to %s", tokensPath)
+			}
+
+			listener, err := jsonrpc.Listen(*listenOpt)
+			if err != nil {
+				exitWithError(withExitCode(exitInvalidInput, err))
+			}
+			server := jsonrpc.NewServer(w, tokens)
+
+			var grpcServer *grpc.Server
+			if *grpcOpt != "" {
+				grpcListener, err := jsonrpc.Listen(*grpcOpt)
+				if err != nil {
+					exitWithError(withExitCode(exitInvalidInput, err))
+				}
+				grpcServer = grpc.NewServer(w, server.Locker(), tokens)
+				go func() {
+					if err := grpcServer.Serve(grpcListener); err != nil {
+						PrintDangerMsg("gRPC server failed: %v", err)
+					}
+				}()
+				PrintInfoMsg("Serving gRPC on %s", grpcListener.Addr())
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if grpcServer != nil {
+					grpcServer.Stop()
+				}
+				_ = server.Shutdown(ctx)
+			}()
+
+			PrintLine()
+			PrintInfoMsg("Serving JSON-RPC 2.0 on %s", listener.Addr())
+			PrintInfoMsg("Methods: %s", strings.Join(jsonrpc.Methods(), ", "))
+			if w.IsEncrypted() {
+				PrintInfoMsg("The wallet is locked, call \"unlock\" to sign the transactions")
+			}
+			if err := server.Serve(listener); err != nil {
+				exitWithError(err)
+			}
+			PrintInfoMsg("Server stopped, the wallet is locked")
+		}
+	}
+}
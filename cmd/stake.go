@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/cmd"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// MoveStake unbonds a validator, withdraws the stake after the unbonding period
+// and optionally bonds it to another validator.
+// The progress is kept in a state file, so it can be resumed by running it again.
+func MoveStake() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		valArg := c.String(cli.StringArg{
+			Name: "VALIDATOR",
+			Desc: "address of the validator to be unbonded",
+		})
+		accArg := c.String(cli.StringArg{
+			Name: "ACCOUNT",
+			Desc: "account address to withdraw the stake to",
+		})
+		bondToOpt := c.String(cli.StringOpt{
+			Name: "bond-to",
+			Desc: "public key of a validator, or a validator address of this wallet, to bond the withdrawn stake to (optional)",
+		})
+		stateOpt := c.String(cli.StringOpt{
+			Name: "state",
+			Desc: "path to the state file, by default it is next to the wallet file",
+		})
+		pollOpt := c.String(cli.StringOpt{
+			Name:  "poll",
+			Desc:  "interval of checking the blockchain state",
+			Value: "1m",
+		})
+		noWaitOpt := c.Bool(cli.BoolOpt{
+			Name:  "no-wait",
+			Desc:  "exit when it has to wait for the blockchain, instead of waiting",
+			Value: false,
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			poll, err := time.ParseDuration(*pollOpt)
+			if err != nil {
+				exitWithError(err)
+			}
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			passphrase := getPassphrase(w)
+			statePath := stakeStatePath(w, *stateOpt, *valArg)
+			var m *wallet.StakeMove
+			if util.PathExists(statePath) {
+				m, err = wallet.LoadStakeMove(statePath)
+				if err != nil {
+					exitWithError(err)
+				}
+				if m.Validator != *valArg || m.Account != *accArg {
+					exitWithError(withExitCode(exitInvalidInput,
+						fmt.Errorf("the state file %s belongs to another stake move", statePath)))
+				}
+				PrintInfoMsg("Resuming from %s", statePath)
+			} else {
+				bondTo := *bondToOpt
+				if w.Role(bondTo) == wallet.RoleValidator {
+					bondTo, err = w.PublicKey(passphrase, bondTo)
+					if err != nil {
+						exitWithError(err)
+					}
+				}
+				m, err = wallet.NewStakeMove(*valArg, *accArg, bondTo)
+				if err != nil {
+					exitWithError(err)
+				}
+
+				PrintLine()
+				PrintInfoMsg("You are going to unbond validator %s, and withdraw its stake to %s", m.Validator, m.Account)
+				if m.BondTo != "" {
+					PrintInfoMsg("The withdrawn stake will be bonded to %s", m.BondTo)
+				}
+				PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
+				if !PromptConfirm("Do you want to continue? ") {
+					exitWithError(errCanceled)
+				}
+				if err := m.Save(statePath); err != nil {
+					exitWithError(err)
+				}
+			}
+
+			PrintLine()
+			for {
+				PrintInfoMsg("Step: %s", m.Describe())
+				progressed, err := w.AdvanceStakeMove(passphrase, m)
+				if saveErr := m.Save(statePath); saveErr != nil {
+					exitWithError(saveErr)
+				}
+				if err != nil {
+					exitWithError(fmt.Errorf("%w, run the command again to retry", err))
+				}
+				if m.IsDone() {
+					PrintSuccessMsg("Stake is moved")
+					printResult(makeStakeMoveResult(m, statePath))
+					return
+				}
+				if !progressed {
+					if *noWaitOpt {
+						PrintInfoMsg("Step: %s", m.Describe())
+						PrintInfoMsg("Run the command again to continue")
+						printResult(makeStakeMoveResult(m, statePath))
+						return
+					}
+					time.Sleep(poll)
+				}
+			}
+		}
+	}
+}
+
+// StakeStatus shows the progress of moving the stake of a validator
+func StakeStatus() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		valArg := c.String(cli.StringArg{
+			Name: "VALIDATOR",
+			Desc: "address of the validator",
+		})
+		stateOpt := c.String(cli.StringOpt{
+			Name: "state",
+			Desc: "path to the state file, by default it is next to the wallet file",
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			statePath := stakeStatePath(w, *stateOpt, *valArg)
+			if !util.PathExists(statePath) {
+				exitWithError(withExitCode(exitNotFound, fmt.Errorf("there is no stake move for %s", *valArg)))
+			}
+			m, err := wallet.LoadStakeMove(statePath)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("Validator: %s", m.Validator)
+			PrintInfoMsg("Account: %s", m.Account)
+			if m.BondTo != "" {
+				PrintInfoMsg("Bond to: %s", m.BondTo)
+			}
+			PrintInfoMsg("Step: %s", m.Describe())
+			PrintInfoMsg("Updated at: %s", m.UpdatedAt.Local().Format(time.RFC1123))
+			printResult(makeStakeMoveResult(m, statePath))
+		}
+	}
+}
+
+// stakeStatePath returns the path of the state file of moving the validator stake
+func stakeStatePath(w *wallet.Wallet, statePath, validator string) string {
+	if statePath != "" {
+		return statePath
+	}
+	return wallet.StakeMovePath(w.Path(), validator)
+}
+
+type stakeMoveResult struct {
+	*wallet.StakeMove
+	Description string `json:"description"`
+	StateFile   string `json:"state_file"`
+}
+
+func makeStakeMoveResult(m *wallet.StakeMove, statePath string) stakeMoveResult {
+	return stakeMoveResult{
+		StakeMove:   m,
+		Description: m.Describe(),
+		StateFile:   statePath,
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	cli "github.com/jawher/mow.cli"
@@ -19,36 +21,44 @@ func SendTx() func(c *cli.Cmd) {
 
 		toArg := c.String(cli.StringArg{
 			Name: "TO",
-			Desc: "receiver address",
+			Desc: "receiver address, or the name of a contact",
 		})
 
 		amountArg := c.String(cli.StringArg{
 			Name: "AMOUNT",
-			Desc: "the amount to be transferred",
+			Desc: "the amount to be transferred, in ZRB (e.g. 1.5), or \"all\" to send the whole balance",
 		})
-		stampOpt, seqOpt, memoOpt, feeOpt := addCommonTxOptions(c)
+		opts := addCommonTxOptions(c)
 
-		c.Before = func() { fmt.Println(cmd.ZARB) }
+		c.Before = func() { printHeader(cmd.ZARB) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			// The fee of a sweep is deducted from the balance
+			if *amountArg == "all" && *opts.fee != "" {
+				exitWithError(withExitCode(exitInvalidInput, errors.New("--fee can't be set when sending all the balance")))
+			}
+			w, err := openWalletForTx(opts)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
-			trx, err := w.MakeSendTx(*stampOpt, *seqOpt, *fromArg, *toArg, *amountArg, *feeOpt, *memoOpt)
+			to := resolveReceiver(w, *toArg)
+			var trx *tx.Tx
+			if *amountArg == "all" {
+				trx, err = w.MakeSweepTx(*opts.stamp, *opts.seq, *fromArg, to, *opts.memo, *opts.force)
+			} else {
+				trx, err = w.MakeSendTx(*opts.stamp, *opts.seq, *fromArg, to, *amountArg, *opts.fee, *opts.memo, *opts.force)
+			}
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("You are going to sign and broadcast a Send transition to the network:")
 			PrintInfoMsg("From: %s", *fromArg)
-			PrintInfoMsg("To: %s", *toArg)
-			PrintInfoMsg("Amount: %s", *amountArg)
+			printReceiver(w, "To", to)
+			PrintInfoMsg("Amount: %s", formatAmount(trx.Payload().Value()))
 
-			signAndPublishTx(w, trx)
+			signAndPublishTx(w, trx, opts)
 		}
 	}
 }
@@ -62,36 +72,42 @@ func BondTx() func(c *cli.Cmd) {
 
 		pubArg := c.String(cli.StringArg{
 			Name: "TO",
-			Desc: "validator public key",
+			Desc: "validator public key, or a validator address of this wallet",
 		})
 
 		stakeArg := c.String(cli.StringArg{
 			Name: "STAKE",
-			Desc: "stake amount",
+			Desc: "stake amount, in ZRB (e.g. 1.5)",
 		})
-		stampOpt, seqOpt, memoOpt, feeOpt := addCommonTxOptions(c)
+		opts := addCommonTxOptions(c)
 
-		c.Before = func() { fmt.Println(cmd.ZARB) }
+		c.Before = func() { printHeader(cmd.ZARB) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWalletForTx(opts)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
+			}
+
+			pub := *pubArg
+			if w.Role(pub) == wallet.RoleValidator {
+				pub, err = w.PublicKey(getPassphrase(w), pub)
+				if err != nil {
+					exitWithError(err)
+				}
 			}
 
-			trx, err := w.MakeBondTx(*stampOpt, *seqOpt, *senderArg, *pubArg, *stakeArg, *feeOpt, *memoOpt)
+			trx, err := w.MakeBondTx(*opts.stamp, *opts.seq, *senderArg, pub, *stakeArg, *opts.fee, *opts.memo, *opts.force)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("You are going to sign and broadcast a bond transition to the network.")
 			PrintInfoMsg("Account: %s", *senderArg)
 			PrintInfoMsg("Validator: %s", trx.Payload().(*payload.BondPayload).PublicKey.Address())
-			PrintInfoMsg("Stake: %s", *stakeArg)
+			PrintInfoMsg("Stake: %s", formatAmount(trx.Payload().Value()))
 
-			signAndPublishTx(w, trx)
+			signAndPublishTx(w, trx, opts)
 		}
 	}
 }
@@ -102,27 +118,25 @@ func UnbondTx() func(c *cli.Cmd) {
 			Name: "ADDR",
 			Desc: "validator's address",
 		})
-		stampOpt, seqOpt, memoOpt, _ := addCommonTxOptions(c)
+		opts := addCommonTxOptions(c)
 
-		c.Before = func() { fmt.Println(cmd.ZARB) }
+		c.Before = func() { printHeader(cmd.ZARB) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWalletForTx(opts)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
-			trx, err := w.MakeUnbondTx(*stampOpt, *seqOpt, *valArg, *memoOpt)
+			trx, err := w.MakeUnbondTx(*opts.stamp, *opts.seq, *valArg, *opts.memo, *opts.force)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("You are going to sign and broadcast an Unbond transition to the network:")
 			PrintInfoMsg("Validator: %s", *valArg)
 
-			signAndPublishTx(w, trx)
+			signAndPublishTx(w, trx, opts)
 
 		}
 	}
@@ -137,84 +151,275 @@ func WithdrawTx() func(c *cli.Cmd) {
 
 		toArg := c.String(cli.StringArg{
 			Name: "TO",
-			Desc: "deposit to account address",
+			Desc: "deposit to account address, or the name of a contact",
 		})
 
 		amountArg := c.String(cli.StringArg{
 			Name: "AMOUNT",
-			Desc: "the amount to be transferred",
+			Desc: "the amount to be transferred, in ZRB (e.g. 1.5)",
 		})
-		stampOpt, seqOpt, memoOpt, feeOpt := addCommonTxOptions(c)
+		opts := addCommonTxOptions(c)
 
-		c.Before = func() { fmt.Println(cmd.ZARB) }
+		c.Before = func() { printHeader(cmd.ZARB) }
 		c.Action = func() {
-			w, err := wallet.OpenWallet(*path)
+			w, err := openWalletForTx(opts)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
-			trx, err := w.MakeWithdrawTx(*stampOpt, *seqOpt, *fromArg, *toArg, *amountArg, *feeOpt, *memoOpt)
+			to := resolveReceiver(w, *toArg)
+			trx, err := w.MakeWithdrawTx(*opts.stamp, *opts.seq, *fromArg, to, *amountArg, *opts.fee, *opts.memo, *opts.force)
 			if err != nil {
-				PrintDangerMsg(err.Error())
-				return
+				exitWithError(err)
 			}
 
 			PrintLine()
 			PrintInfoMsg("You are going to sign and broadcast a Withdraw transition to the network.")
 			PrintInfoMsg("Validator: %s", *fromArg)
-			PrintInfoMsg("Account: %s", *toArg)
-			PrintInfoMsg("Amount: %s", *amountArg)
+			printReceiver(w, "Account", to)
+			PrintInfoMsg("Amount: %s", formatAmount(trx.Payload().Value()))
 
-			signAndPublishTx(w, trx)
+			signAndPublishTx(w, trx, opts)
 		}
 	}
 }
 
-func addCommonTxOptions(c *cli.Cmd) (*string, *string, *string, *string) {
-	stampOpt := c.String(cli.StringOpt{
-		Name: "stamp",
-		Desc: "transaction stamp, if not specified will query from gRPC server",
-	})
+// SortitionTx signs and publishes a sortition transaction with a validator key of the wallet
+func SortitionTx() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		valArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "validator's address",
+		})
+		proofArg := c.String(cli.StringArg{
+			Name: "PROOF",
+			Desc: "sortition proof (VRF) in hex",
+		})
+		stampOpt := c.String(cli.StringOpt{
+			Name: "stamp",
+			Desc: "stamp of the block that the proof is generated for, if not specified will query from gRPC server",
+		})
+		seqOpt := c.String(cli.StringOpt{
+			Name: "seq",
+			Desc: "transaction sequence, if not specified will query from gRPC server",
+		})
+		dryRunOpt := c.Bool(cli.BoolOpt{
+			Name:  "dry-run",
+			Desc:  "sign and check the transaction against the blockchain state, without broadcasting it",
+			Value: false,
+		})
+		forceOpt := c.Bool(cli.BoolOpt{
+			Name:  "force",
+			Desc:  "skip checking the transaction against the blockchain state",
+			Value: false,
+		})
+		// Sortition transactions have no fee and no memo
+		opts := txOptions{
+			stamp:       stampOpt,
+			seq:         seqOpt,
+			memo:        new(string),
+			fee:         new(string),
+			dryRun:      dryRunOpt,
+			force:       forceOpt,
+			feePriority: new(string),
+		}
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWalletForTx(opts)
+			if err != nil {
+				exitWithError(err)
+			}
+			if w.Role(*valArg) != wallet.RoleValidator {
+				PrintWarnMsg("%s is not a validator address of this wallet", *valArg)
+			}
+
+			trx, err := w.MakeSortitionTx(*opts.stamp, *opts.seq, *valArg, *proofArg, *opts.force)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to sign and broadcast a Sortition transition to the network:")
+			PrintInfoMsg("Validator: %s", *valArg)
+			PrintInfoMsg("Stamp: %s", trx.Stamp())
+
+			signAndPublishTx(w, trx, opts)
+		}
+	}
+}
 
-	seqOpt := c.String(cli.StringOpt{
+// ResubmitTx rebuilds a pending transaction with a fresh stamp and publishes it
+func ResubmitTx() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		idArg := c.String(cli.StringArg{
+			Name: "TXID",
+			Desc: "id of the pending transaction",
+		})
+		opts := addSigningTxOptions(c)
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWalletForTx(opts)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			trx, err := w.MakeResubmitTx(*idArg, *opts.stamp, *opts.fee, *opts.force)
+			if errors.Is(err, wallet.ErrTxConfirmed) {
+				PrintSuccessMsg("Transaction is already confirmed, there is no need to resubmit it")
+				printResult(struct {
+					ID        string `json:"id"`
+					Confirmed bool   `json:"confirmed"`
+				}{*idArg, true})
+				return
+			}
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to resubmit a %s transaction with a new stamp:", trx.Payload().Type())
+			printTx(w, trx)
+
+			signAndPublishTx(w, trx, opts)
+		}
+	}
+}
+
+type txOptions struct {
+	stamp       *string
+	seq         *string
+	memo        *string
+	fee         *string
+	dryRun      *bool
+	force       *bool
+	feePriority *string
+}
+
+func addCommonTxOptions(c *cli.Cmd) txOptions {
+	opts := addSigningTxOptions(c)
+	opts.seq = c.String(cli.StringOpt{
 		Name: "seq",
 		Desc: "transaction sequence, if not specified will query from gRPC server",
 	})
-	memoOpt := c.String(cli.StringOpt{
+	opts.memo = c.String(cli.StringOpt{
 		Name:  "memo",
 		Desc:  "transaction memo, maximum should be 64 character (optional)",
 		Value: "",
 	})
+	return opts
+}
+
+// addSigningTxOptions adds the options for signing and publishing a transaction
+func addSigningTxOptions(c *cli.Cmd) txOptions {
+	stampOpt := c.String(cli.StringOpt{
+		Name: "stamp",
+		Desc: "transaction stamp, if not specified will query from gRPC server",
+	})
 	feeOpt := c.String(cli.StringOpt{
 		Name:  "fee",
-		Desc:  "transaction fee, if not specified will calculate automatically",
+		Desc:  "transaction fee in ZRB, if not specified will calculate automatically",
 		Value: "",
 	})
+	dryRunOpt := c.Bool(cli.BoolOpt{
+		Name:  "dry-run",
+		Desc:  "sign and check the transaction against the blockchain state, without broadcasting it",
+		Value: false,
+	})
+	forceOpt := c.Bool(cli.BoolOpt{
+		Name:  "force",
+		Desc:  "skip checking the transaction against the blockchain state",
+		Value: false,
+	})
+	feePriorityOpt := c.String(cli.StringOpt{
+		Name:  "fee-priority",
+		Desc:  "transaction fee priority: low, normal or high",
+		Value: "normal",
+	})
 
-	return stampOpt, seqOpt, memoOpt, feeOpt
+	return txOptions{
+		stamp:       stampOpt,
+		fee:         feeOpt,
+		dryRun:      dryRunOpt,
+		force:       forceOpt,
+		feePriority: feePriorityOpt,
+	}
 }
 
-func signAndPublishTx(w *wallet.Wallet, trx *tx.Tx) {
+func openWalletForTx(opts txOptions) (*wallet.Wallet, error) {
+	priority, err := wallet.FeePriorityFromString(*opts.feePriority)
+	if err != nil {
+		return nil, withExitCode(exitInvalidInput, err)
+	}
+	w, err := openWallet()
+	if err != nil {
+		return nil, err
+	}
+	w.SetFeePriority(priority)
+	return w, nil
+}
+
+func signAndPublishTx(w *wallet.Wallet, trx *tx.Tx, opts txOptions) {
+	if !trx.IsFreeTx() {
+		if *opts.fee != "" {
+			PrintInfoMsg("Fee: %s (set manually)", formatAmount(trx.Fee()))
+		} else {
+			PrintInfoMsg("Fee: %s (%s)", formatAmount(trx.Fee()), w.ExplainFee(trx.Payload().Value()))
+		}
+	}
+
+	if *opts.dryRun {
+		simulateTx(w, trx)
+		return
+	}
+
 	PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
 	confirmed := PromptConfirm("Do you want to continue? ")
 	if !confirmed {
-		return
+		exitWithError(errCanceled)
 	}
 
 	passphrase := getPassphrase(w)
 	res, err := w.SignAndBroadcast(passphrase, trx)
 	if err != nil {
-		PrintDangerMsg(err.Error())
-		return
+		exitWithError(err)
 	}
 	PrintInfoMsg(res)
+	printTxResult(w, trx, true)
 }
 
-func getPassphrase(w *wallet.Wallet) string {
-	passphrase := ""
-	if w.IsEncrypted() {
-		passphrase = PromptPassphrase("Wallet password: ", false)
+func simulateTx(w *wallet.Wallet, trx *tx.Tx) {
+	PrintWarnMsg("Dry run, the transaction will not be broadcasted")
+
+	passphrase := getPassphrase(w)
+	bs, err := w.SimulateTx(passphrase, trx)
+	if err != nil {
+		exitWithError(fmt.Errorf("transaction will be rejected: %w", err))
 	}
-	return passphrase
+
+	PrintLine()
+	PrintInfoMsg("ID: %s", trx.ID())
+	PrintInfoMsg("Signed transaction: %x", bs)
+	PrintSuccessMsg("Transaction is valid")
+	printTxResult(w, trx, false)
+}
+
+// txResult is the result of signing a transaction, in JSON format
+type txResult struct {
+	*txInfo
+	Broadcasted bool   `json:"broadcasted"`
+	Raw         string `json:"raw"`
+}
+
+// printTxResult prints the signed transaction as the result of the command
+func printTxResult(w *wallet.Wallet, trx *tx.Tx, broadcasted bool) {
+	info := makeTxInfo(w, trx)
+	info.Valid = true
+	bs, _ := trx.Bytes()
+	printResult(txResult{
+		txInfo:      info,
+		Broadcasted: broadcasted,
+		Raw:         hex.EncodeToString(bs),
+	})
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/cmd"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// BatchTx signs and broadcasts the payments in a CSV or JSON file
+func BatchTx() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		fileArg := c.String(cli.StringArg{
+			Name: "FILE",
+			Desc: "a CSV file with \"from,to,amount[,memo]\" lines, or a JSON file",
+		})
+		reportOpt := c.String(cli.StringOpt{
+			Name: "report",
+			Desc: "a path to the report file, default is FILE.report.csv",
+		})
+		rateOpt := c.Int(cli.IntOpt{
+			Name:  "rate",
+			Desc:  "maximum number of transactions to broadcast per second",
+			Value: 5,
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			if *rateOpt <= 0 {
+				exitWithError(withExitCode(exitInvalidInput, errors.New("rate should be positive")))
+			}
+			reportPath := *reportOpt
+			if reportPath == "" {
+				reportPath = *fileArg + ".report.csv"
+			}
+
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+			payments, err := wallet.ReadBatchFile(*fileArg)
+			if err != nil {
+				exitWithError(err)
+			}
+			previous, err := wallet.ReadBatchReport(reportPath)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			done := 0
+			for _, res := range previous {
+				if res.TxID != "" {
+					done++
+				}
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to sign and broadcast %v payments.", len(payments)-done)
+			if done > 0 {
+				PrintInfoMsg("%v payments are already sent, based on the report: %s", done, reportPath)
+			}
+			PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
+			confirmed := PromptConfirm("Do you want to continue? ")
+			if !confirmed {
+				exitWithError(errCanceled)
+			}
+
+			passphrase := getPassphrase(w)
+			interval := time.Second / time.Duration(*rateOpt)
+			results := []wallet.BatchResult{}
+			err = w.SendBatch(passphrase, payments, previous, interval, func(res wallet.BatchResult) {
+				results = append(results, res)
+				if err := wallet.AppendBatchReport(reportPath, res); err != nil {
+					PrintErrorMsg("Failed to write the report: %v", err)
+				}
+				if res.Error != "" {
+					PrintDangerMsg("line %v: %s", res.Line, res.Error)
+				} else {
+					PrintInfoMsg("line %v: %s sent to %s, %s", res.Line, res.Amount.Format(*rawUnits), res.To, res.TxID)
+				}
+			})
+			PrintLine()
+			if err != nil {
+				exitWithError(err)
+			}
+			PrintSuccessMsg("All payments are sent, the report is at: %s", reportPath)
+			printResult(struct {
+				Report  string               `json:"report"`
+				Results []wallet.BatchResult `json:"results"`
+			}{reportPath, results})
+		}
+	}
+}
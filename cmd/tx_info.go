@@ -0,0 +1,328 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/cmd"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// TxStatus shows whether a transaction is committed or not
+func TxStatus() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		idArg := c.String(cli.StringArg{
+			Name: "TXID",
+			Desc: "transaction id",
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			trx, err := w.GetTransaction(*idArg)
+			if errors.Is(err, wallet.ErrTxNotFound) {
+				PrintWarnMsg("Status: not found (pending, expired or unknown)")
+				printResult(txStatus{ID: *idArg, Status: "not_found"})
+				return
+			}
+			if err != nil {
+				exitWithError(err)
+			}
+			PrintSuccessMsg("Status: confirmed")
+			PrintInfoMsg("Type: %s", trx.Payload().Type())
+			PrintInfoMsg("Signer: %s", addressWithLabel(w, trx.Payload().Signer()))
+			printResult(txStatus{
+				ID:     trx.ID().String(),
+				Status: "confirmed",
+				Type:   trx.Payload().Type().String(),
+				Signer: trx.Payload().Signer().String(),
+			})
+		}
+	}
+}
+
+// TxShow fetches a transaction from the node and shows its details
+func TxShow() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		idArg := c.String(cli.StringArg{
+			Name: "TXID",
+			Desc: "transaction id",
+		})
+
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			trx, err := w.GetTransaction(*idArg)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("Status: confirmed")
+			printTx(w, trx)
+			info := makeTxInfo(w, trx)
+			info.Valid = true
+			printResult(info)
+		}
+	}
+}
+
+// TxDecode decodes a raw transaction and shows its details.
+// It works offline, the wallet is only used to mark its addresses.
+func TxDecode() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		dataArg := c.String(cli.StringArg{
+			Name: "DATA",
+			Desc: "raw transaction in hex, or a path to a file containing it",
+		})
+		jsonOpt := c.Bool(cli.BoolOpt{
+			Name:  "json",
+			Desc:  "print the transaction in JSON format, the same as \"--output json\"",
+			Value: false,
+		})
+
+		c.Before = func() {
+			if *jsonOpt {
+				useJSONOutput()
+			}
+			printHeader(header)
+		}
+		c.Action = func() {
+			data := []byte(*dataArg)
+			if util.PathExists(*dataArg) {
+				var err error
+				data, err = util.ReadFile(*dataArg)
+				if err != nil {
+					exitWithError(err)
+				}
+			}
+
+			trx, err := wallet.DecodeTx(data)
+			if err != nil {
+				exitWithError(withExitCode(exitInvalidInput, err))
+			}
+
+			// The wallet is optional here
+			w, _ := openWallet()
+
+			sigErr := trx.SanityCheck()
+			info := makeTxInfo(w, trx)
+			info.Valid = sigErr == nil
+			if sigErr != nil {
+				info.Error = sigErr.Error()
+			}
+			printResult(info)
+
+			PrintLine()
+			printTx(w, trx)
+			if trx.PublicKey() != nil {
+				PrintInfoMsg("Public key: %s", trx.PublicKey())
+				PrintInfoMsg("Signature: %s", trx.Signature())
+			}
+			if w != nil && w.Contains(trx.Payload().Signer().String()) {
+				PrintInfoMsg("Signer belongs to this wallet")
+			}
+			if sigErr != nil {
+				PrintDangerMsg("Invalid transaction: %s", sigErr.Error())
+				cli.Exit(exitSignature)
+			}
+			PrintSuccessMsg("Signature is valid")
+		}
+	}
+}
+
+// TxPending shows the transactions that are broadcasted but not committed yet
+func TxPending() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Before = func() { printHeader(cmd.ZARB) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			pending, err := w.PendingTxs()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			if len(pending) == 0 {
+				PrintInfoMsg("There is no pending transaction")
+			}
+			infos := []pendingTxInfo{}
+			for _, p := range pending {
+				if p.Expired {
+					PrintWarnMsg("%s %-9s seq: %-4d %s (expired at height %d, resubmit it with \"tx resubmit\")",
+						p.ID, p.Type, p.Sequence, p.Signer, p.ExpiryHeight())
+				} else {
+					PrintInfoMsg("%s %-9s seq: %-4d %s (expires after height %d)",
+						p.ID, p.Type, p.Sequence, p.Signer, p.ExpiryHeight())
+				}
+				infos = append(infos, pendingTxInfo{
+					ID:           p.ID,
+					Type:         p.Type.String(),
+					Signer:       p.Signer,
+					Sequence:     p.Sequence,
+					Value:        p.Value,
+					Height:       p.Height,
+					ExpiryHeight: p.ExpiryHeight(),
+					Expired:      p.Expired,
+					CreatedAt:    p.CreatedAt,
+				})
+			}
+			printResult(infos)
+		}
+	}
+}
+
+type txStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Type   string `json:"type,omitempty"`
+	Signer string `json:"signer,omitempty"`
+}
+
+type pendingTxInfo struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Signer       string    `json:"signer"`
+	Sequence     int32     `json:"sequence"`
+	Value        int64     `json:"value"`
+	Height       int32     `json:"height"`
+	ExpiryHeight int32     `json:"expiry_height"`
+	Expired      bool      `json:"expired"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type txInfo struct {
+	ID        string `json:"id"`
+	Version   uint8  `json:"version"`
+	Type      string `json:"type"`
+	Stamp     string `json:"stamp"`
+	Sequence  int32  `json:"sequence"`
+	Signer    string `json:"signer"`
+	Owned     bool   `json:"owned"`
+	Sender    string `json:"sender,omitempty"`
+	Receiver  string `json:"receiver,omitempty"`
+	Validator string `json:"validator,omitempty"`
+	PublicKey string `json:"validator_public_key,omitempty"`
+	Proof     string `json:"proof,omitempty"`
+	Amount    int64  `json:"amount"`
+	Fee       int64  `json:"fee"`
+	Memo      string `json:"memo"`
+	SignerKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Valid     bool   `json:"valid"`
+	Error     string `json:"error,omitempty"`
+}
+
+func makeTxInfo(w *wallet.Wallet, trx *tx.Tx) *txInfo {
+	info := &txInfo{
+		ID:       trx.ID().String(),
+		Version:  trx.Version(),
+		Type:     trx.Payload().Type().String(),
+		Stamp:    trx.Stamp().String(),
+		Sequence: trx.Sequence(),
+		Signer:   trx.Payload().Signer().String(),
+		Amount:   trx.Payload().Value(),
+		Fee:      trx.Fee(),
+		Memo:     trx.Memo(),
+	}
+	if w != nil {
+		info.Owned = w.Contains(info.Signer)
+	}
+	if trx.PublicKey() != nil {
+		info.SignerKey = trx.PublicKey().String()
+		info.Signature = trx.Signature().String()
+	}
+
+	switch pld := trx.Payload().(type) {
+	case *payload.SendPayload:
+		info.Sender = pld.Sender.String()
+		info.Receiver = pld.Receiver.String()
+	case *payload.BondPayload:
+		info.Sender = pld.Sender.String()
+		info.Validator = pld.PublicKey.Address().String()
+		info.PublicKey = pld.PublicKey.String()
+	case *payload.UnbondPayload:
+		info.Validator = pld.Validator.String()
+	case *payload.WithdrawPayload:
+		info.Validator = pld.From.String()
+		info.Receiver = pld.To.String()
+	case *payload.SortitionPayload:
+		proof, _ := pld.Proof.MarshalText()
+		info.Validator = pld.Address.String()
+		info.Proof = string(proof)
+	}
+	return info
+}
+
+func printTx(w *wallet.Wallet, trx *tx.Tx) {
+	PrintInfoMsg("ID: %s", trx.ID())
+	PrintInfoMsg("Type: %s", trx.Payload().Type())
+	PrintInfoMsg("Stamp: %s", trx.Stamp())
+	PrintInfoMsg("Sequence: %d", trx.Sequence())
+	if trx.PublicKey() != nil {
+		PrintInfoMsg("Signer: %s", addressWithLabel(w, trx.Payload().Signer()))
+	}
+
+	switch pld := trx.Payload().(type) {
+	case *payload.SendPayload:
+		PrintInfoMsg("From: %s", addressWithLabel(w, pld.Sender))
+		PrintInfoMsg("To: %s", addressWithLabel(w, pld.Receiver))
+		PrintInfoMsg("Amount: %s", formatAmount(pld.Amount))
+	case *payload.BondPayload:
+		PrintInfoMsg("Account: %s", addressWithLabel(w, pld.Sender))
+		PrintInfoMsg("Validator: %s", addressWithLabel(w, pld.PublicKey.Address()))
+		PrintInfoMsg("Validator public key: %s", pld.PublicKey)
+		PrintInfoMsg("Stake: %s", formatAmount(pld.Stake))
+	case *payload.UnbondPayload:
+		PrintInfoMsg("Validator: %s", addressWithLabel(w, pld.Validator))
+	case *payload.WithdrawPayload:
+		PrintInfoMsg("Validator: %s", addressWithLabel(w, pld.From))
+		PrintInfoMsg("Account: %s", addressWithLabel(w, pld.To))
+		PrintInfoMsg("Amount: %s", formatAmount(pld.Amount))
+	case *payload.SortitionPayload:
+		proof, _ := pld.Proof.MarshalText()
+		PrintInfoMsg("Validator: %s", addressWithLabel(w, pld.Address))
+		PrintInfoMsg("Proof: %s", proof)
+	}
+
+	PrintInfoMsg("Fee: %s", formatAmount(trx.Fee()))
+	PrintInfoMsg("Memo: %s", trx.Memo())
+}
+
+// addressWithLabel marks the addresses that belong to the wallet or to a contact
+func addressWithLabel(w *wallet.Wallet, addr crypto.Address) string {
+	addrStr := addr.String()
+	if w == nil {
+		return addrStr
+	}
+	if !w.Contains(addrStr) {
+		if c, ok := w.ContactByAddress(addrStr); ok {
+			return fmt.Sprintf("%s (contact: %s)", addrStr, c.Name)
+		}
+		return addrStr
+	}
+	label := w.Label(addrStr)
+	if label == "" {
+		return fmt.Sprintf("%s (wallet)", addrStr)
+	}
+	return fmt.Sprintf("%s (wallet: %s)", addrStr, label)
+}
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"os/user"
 	"strings"
 
@@ -65,9 +63,9 @@ func (p *terminalPrompter) PromptPassword(prompt string) (string, error) {
 		p.warned = true
 	}
 	// Just as in Prompt, handle printing the prompt here instead of relying on liner.
-	fmt.Print(prompt)
+	fmt.Fprint(textOut, prompt)
 	pass, err := p.State.Prompt("")
-	fmt.Println()
+	fmt.Fprintln(textOut)
 	return pass, err
 }
 
@@ -81,9 +79,9 @@ func (p *terminalPrompter) PromptInput(prompt string) (string, error) {
 		// liner tries to be smart about printing the prompt
 		// and doesn't print anything if input is redirected.
 		// Un-smart it by printing the prompt always.
-		fmt.Print(prompt)
+		fmt.Fprint(textOut, prompt)
 		prompt = ""
-		defer fmt.Println()
+		defer fmt.Fprintln(textOut)
 	}
 	return p.State.Prompt(prompt)
 }
@@ -103,19 +101,16 @@ func (p *terminalPrompter) PromptConfirm(prompt string) (bool, error) {
 func PromptPassphrase(prompt string, confirmation bool) string {
 	passphrase, err := Stdin.PromptPassword(prompt)
 	if err != nil {
-		PrintErrorMsg("Failed to read passphrase: %v", err)
-		os.Exit(1)
+		exitWithError(fmt.Errorf("failed to read passphrase: %w", err))
 	}
 
 	if confirmation {
 		confirm, err := Stdin.PromptPassword("Repeat passphrase: ")
 		if err != nil {
-			PrintErrorMsg("Failed to read passphrase confirmation: %v", err)
-			os.Exit(1)
+			exitWithError(fmt.Errorf("failed to read passphrase confirmation: %w", err))
 		}
 		if passphrase != confirm {
-			PrintErrorMsg("Passphrases do not match")
-			os.Exit(1)
+			exitWithError(withExitCode(exitInvalidInput, errors.New("passphrases do not match")))
 		}
 	}
 
@@ -124,10 +119,12 @@ func PromptPassphrase(prompt string, confirmation bool) string {
 
 // PromptConfirm prompts user to confirm the operation
 func PromptConfirm(prompt string) bool {
+	if *assumeYes {
+		return true
+	}
 	input, err := Stdin.PromptConfirm(prompt)
 	if err != nil {
-		PrintErrorMsg("Failed to read input: %v", err)
-		os.Exit(1)
+		exitWithError(fmt.Errorf("failed to read input: %w", err))
 	}
 	return input
 }
@@ -136,8 +133,7 @@ func PromptConfirm(prompt string) bool {
 func PromptInput(prompt string) string {
 	input, err := Stdin.PromptInput(prompt)
 	if err != nil {
-		PrintErrorMsg("Failed to read input: %v", err)
-		os.Exit(1)
+		exitWithError(fmt.Errorf("failed to read input: %w", err))
 	}
 	return input
 }
@@ -146,8 +142,7 @@ func PromptInput(prompt string) string {
 func PromptInputWithSuggestion(prompt, suggestion string) string {
 	input, err := Stdin.PromptWithSuggestion(prompt, suggestion, 0)
 	if err != nil {
-		PrintErrorMsg("Failed to read input: %v", err)
-		os.Exit(1)
+		exitWithError(fmt.Errorf("failed to read input: %w", err))
 	}
 	return input
 }
@@ -156,55 +151,36 @@ func PrintDangerMsg(format string, a ...interface{}) {
 	if liner.TerminalSupported() {
 		format = fmt.Sprintf("\033[31m%s\033[0m\n", format)
 	}
-	fmt.Printf(format, a...)
+	fmt.Fprintf(textOut, format, a...)
 }
 
 func PrintErrorMsg(format string, a ...interface{}) {
 	if liner.TerminalSupported() {
 		format = fmt.Sprintf("\033[31m[ERROR] %s\033[0m\n", format) //Print error msg with red color
 	}
-	fmt.Printf(format, a...)
+	fmt.Fprintf(textOut, format, a...)
 }
 
 func PrintSuccessMsg(format string, a ...interface{}) {
 	if liner.TerminalSupported() {
 		format = fmt.Sprintf("\033[32m%s\033[0m\n", format) //Print successful msg with green color
 	}
-	fmt.Printf(format, a...)
+	fmt.Fprintf(textOut, format, a...)
 }
 
 func PrintWarnMsg(format string, a ...interface{}) {
 	if liner.TerminalSupported() {
 		format = fmt.Sprintf("\033[33m%s\033[0m\n", format) //Print warning msg with yellow color
 	}
-	fmt.Printf(format, a...)
+	fmt.Fprintf(textOut, format, a...)
 }
 
 func PrintInfoMsg(format string, a ...interface{}) {
-	fmt.Printf(format+"\n", a...)
+	fmt.Fprintf(textOut, format+"\n", a...)
 }
 
 func PrintLine() {
-	fmt.Println()
-}
-
-func PrintJSONData(data []byte) {
-	var out bytes.Buffer
-	err := json.Indent(&out, data, "", "   ")
-	if err != nil {
-		PrintErrorMsg("json.Indent error: %s", err)
-		return
-	}
-	PrintInfoMsg(out.String())
-}
-
-func PrintJSONObject(obj interface{}) {
-	data, err := json.Marshal(obj)
-	if err != nil {
-		PrintErrorMsg("json.Marshal error: %s", err)
-		return
-	}
-	PrintJSONData(data)
+	fmt.Fprintln(textOut)
 }
 
 func ZarbHomeDir() string {
@@ -0,0 +1,159 @@
+package main
+
+import (
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-go/crypto"
+)
+
+// NewValidator derives a new validator key from the wallet seed
+func NewValidator() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		labelOpt := addLabelOption(c)
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			label := labelOpt.get()
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			passphrase := getPassphrase(w)
+			addr, err := w.NewValidatorAddress(passphrase, label)
+			if err != nil {
+				exitWithError(err)
+			}
+			pub, err := w.PublicKey(passphrase, addr)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("Address: %s", addr)
+			PrintInfoMsg("Public Key: %s", pub)
+			printResult(struct {
+				Address   string `json:"address"`
+				Label     string `json:"label"`
+				PublicKey string `json:"public_key"`
+			}{addr, label, pub})
+		}
+	}
+}
+
+// ListValidators lists the validator addresses of the wallet
+func ListValidators() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			list := makeAddressList(w, w.ValidatorAddresses())
+			for _, info := range list {
+				PrintInfoMsg("%s %s", info.Address, info.Label)
+			}
+			printResult(list)
+		}
+	}
+}
+
+// ExportValidatorKey writes the validator private key into a key file for the node
+func ExportValidatorKey() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "validator address",
+		})
+		fileOpt := c.String(cli.StringOpt{
+			Name:  "file",
+			Desc:  "path to the key file, it can be used as the validator_key file of the node",
+			Value: "validator_key",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			passphrase := getPassphrase(w)
+			err = w.ExportValidatorKey(passphrase, *addrArg, *fileOpt)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintWarnMsg("The validator key is written to %s, keep it safe", *fileOpt)
+			printResult(struct {
+				Address string `json:"address"`
+				File    string `json:"file"`
+			}{*addrArg, *fileOpt})
+		}
+	}
+}
+
+// ValidatorStatus shows the state of a validator in the blockchain
+func ValidatorStatus() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		addrArg := c.String(cli.StringArg{
+			Name: "ADDR",
+			Desc: "validator address",
+		})
+		jsonOpt := c.Bool(cli.BoolOpt{
+			Name:  "json",
+			Desc:  "print the status in JSON format, the same as \"--output json\"",
+			Value: false,
+		})
+
+		c.Before = func() {
+			if *jsonOpt {
+				useJSONOutput()
+			}
+			printHeader(header)
+		}
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			status, err := w.GetValidatorStatus(*addrArg)
+			if err != nil {
+				exitWithError(err)
+			}
+			printResult(status)
+
+			PrintLine()
+			addr, _ := crypto.AddressFromString(status.Address)
+			PrintInfoMsg("Address: %s", addressWithLabel(w, addr))
+			PrintInfoMsg("Public key: %s", status.PublicKey)
+			PrintInfoMsg("Number: %d", status.Number)
+			PrintInfoMsg("Sequence: %d", status.Sequence)
+			PrintInfoMsg("Stake: %s", formatAmount(int64(status.Stake)))
+			PrintInfoMsg("Last bonding height: %d", status.LastBondingHeight)
+			if status.LastJoinedHeight > 0 {
+				PrintInfoMsg("Last joined the committee at height: %d", status.LastJoinedHeight)
+			} else {
+				PrintInfoMsg("Never joined the committee")
+			}
+			PrintInfoMsg("Current height: %d", status.CurrentHeight)
+
+			switch {
+			case status.UnbondingHeight == 0 && status.SortitionEligible:
+				PrintSuccessMsg("Bonded, can join the committee")
+			case status.UnbondingHeight == 0:
+				PrintWarnMsg("Bonded, waiting for the bonding period to join the committee")
+			case status.CanWithdraw:
+				PrintInfoMsg("Unbonding height: %d", status.UnbondingHeight)
+				PrintSuccessMsg("Unbonded, the stake can be withdrawn")
+			default:
+				PrintInfoMsg("Unbonding height: %d", status.UnbondingHeight)
+				PrintWarnMsg("Unbonding, the stake can be withdrawn at height %d", status.WithdrawHeight)
+			}
+		}
+	}
+}
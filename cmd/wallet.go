@@ -0,0 +1,218 @@
+package main
+
+import (
+	cli "github.com/jawher/mow.cli"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// SweepImported moves the balance of all imported addresses into a new address
+func SweepImported() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			w, err := openWallet()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			addrs := []string{}
+			for _, addr := range w.ImportedAddresses() {
+				balance, _, err := w.GetBalance(addr)
+				if err != nil {
+					exitWithError(err)
+				}
+				if balance == 0 {
+					continue
+				}
+				PrintInfoMsg("%s %s", addr, balance.Format(*rawUnits))
+				addrs = append(addrs, addr)
+			}
+			if len(addrs) == 0 {
+				PrintInfoMsg("There is nothing to sweep")
+				printResult(sweepResult{Swept: []sweptAddress{}})
+				return
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to move the balance of the imported addresses into a new address.")
+			PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
+			confirmed := PromptConfirm("Do you want to continue? ")
+			if !confirmed {
+				exitWithError(errCanceled)
+			}
+
+			passphrase := getPassphrase(w)
+			receiver, err := w.NewAddress(passphrase, "sweep")
+			if err != nil {
+				exitWithError(err)
+			}
+			PrintInfoMsg("New address: %s", receiver)
+
+			result := sweepResult{Receiver: receiver, Swept: []sweptAddress{}}
+			for _, addr := range addrs {
+				swept := sweptAddress{Address: addr}
+				trx, err := w.MakeSweepTx("", "", addr, receiver, "sweep", false)
+				if err == nil {
+					swept.Amount = trx.Payload().Value()
+					swept.TxID, err = w.SignAndBroadcast(passphrase, trx)
+				}
+				if err != nil {
+					PrintDangerMsg("%s: %s", addr, err.Error())
+					swept.Error = err.Error()
+				} else {
+					PrintInfoMsg("%s: %s sent, %s", addr, formatAmount(swept.Amount), swept.TxID)
+				}
+				result.Swept = append(result.Swept, swept)
+			}
+			printResult(result)
+		}
+	}
+}
+
+type sweptAddress struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+	TxID    string `json:"tx_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type sweepResult struct {
+	Receiver string         `json:"receiver,omitempty"`
+	Swept    []sweptAddress `json:"swept"`
+}
+
+type walletListItem struct {
+	wallet.WalletInfo
+	NetworkName string `json:"network_name"`
+	Current     bool   `json:"current"`
+}
+
+// ListWallets shows the wallets in the wallets directory
+func ListWallets() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			m := walletManager()
+			infos, err := m.Wallets()
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			if len(infos) == 0 {
+				PrintInfoMsg("There is no wallet in %s", ZarbWalletsDir())
+			}
+			current := m.Current()
+			items := []walletListItem{}
+			for _, info := range infos {
+				item := walletListItem{
+					WalletInfo:  info,
+					NetworkName: wallet.Network(info.Network).String(),
+					Current:     info.Name == current,
+				}
+				marker := " "
+				if item.Current {
+					marker = "*"
+				}
+				if info.Error != "" {
+					PrintWarnMsg("%s %-20s %s", marker, info.Name, info.Error)
+					items = append(items, item)
+					continue
+				}
+				encrypted := "not encrypted"
+				if info.Encrypted {
+					encrypted = "encrypted"
+				}
+				PrintInfoMsg("%s %-20s %s %-8s %-13s %d addresses",
+					marker, info.Name, info.UUID, item.NetworkName, encrypted, info.AddressCount)
+				items = append(items, item)
+			}
+			printResult(items)
+		}
+	}
+}
+
+// UseWallet sets the current wallet, that is used when the wallet path is not given
+func UseWallet() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		nameArg := c.String(cli.StringArg{
+			Name: "NAME",
+			Desc: "name of the wallet in the wallets directory",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			m := walletManager()
+			if err := m.Use(*nameArg); err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintSuccessMsg("Current wallet: %s", *nameArg)
+			printResult(walletInfo{Path: m.CurrentPath()})
+		}
+	}
+}
+
+// RenameWallet renames a wallet in the wallets directory
+func RenameWallet() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		c.Spec = "OLD NEW"
+		oldArg := c.String(cli.StringArg{
+			Name: "OLD",
+			Desc: "name of the wallet",
+		})
+		newArg := c.String(cli.StringArg{
+			Name: "NEW",
+			Desc: "new name of the wallet",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			m := walletManager()
+			if err := m.Rename(*oldArg, *newArg); err != nil {
+				exitWithError(err)
+			}
+			path, _ := m.Path(*newArg)
+
+			PrintLine()
+			PrintSuccessMsg("Wallet %s is renamed to %s", *oldArg, *newArg)
+			printResult(walletInfo{Path: path})
+		}
+	}
+}
+
+// DeleteWallet deletes a wallet file from the wallets directory
+func DeleteWallet() func(c *cli.Cmd) {
+	return func(c *cli.Cmd) {
+		nameArg := c.String(cli.StringArg{
+			Name: "NAME",
+			Desc: "name of the wallet in the wallets directory",
+		})
+
+		c.Before = func() { printHeader(header) }
+		c.Action = func() {
+			m := walletManager()
+			info, err := m.Info(*nameArg)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			PrintLine()
+			PrintInfoMsg("You are going to delete the wallet %s with %d addresses.", info.Name, info.AddressCount)
+			PrintWarnMsg("Without the seed phrase, the funds of this wallet will be lost.")
+			PrintWarnMsg("THIS ACTION IS NOT REVERSIBLE")
+			confirmed := PromptConfirm("Do you want to continue? ")
+			if !confirmed {
+				exitWithError(errCanceled)
+			}
+
+			if err := m.Delete(info.Name); err != nil {
+				exitWithError(err)
+			}
+			PrintSuccessMsg("Wallet %s is deleted", info.Name)
+			printResult(walletInfo{Path: info.Path})
+		}
+	}
+}
@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/google/uuid v1.3.0
+	github.com/herumi/bls-go-binary v1.0.1-0.20220328013147-ab19a9ee50c7
 	github.com/jawher/mow.cli v1.2.0
 	github.com/peterh/liner v1.2.1
 	github.com/stretchr/testify v1.7.0
@@ -11,6 +12,7 @@ require (
 	github.com/zarbchain/zarb-go v0.9.1-0.20220404031026-7ee71e53551e
 	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 	google.golang.org/grpc v1.42.0
+	google.golang.org/protobuf v1.27.1
 )
 
 require (
@@ -19,7 +21,6 @@ require (
 	github.com/fxamacker/cbor/v2 v2.2.0 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.7.0 // indirect
-	github.com/herumi/bls-go-binary v1.0.1-0.20220328013147-ab19a9ee50c7 // indirect
 	github.com/mattn/go-runewidth v0.0.8 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
@@ -27,6 +28,5 @@ require (
 	golang.org/x/sys v0.0.0-20210816183151-1e6c022a8912 // indirect
 	golang.org/x/text v0.3.7 // indirect
 	google.golang.org/genproto v0.0.0-20211118181313-81c1377c94b1 // indirect
-	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"strings"
+	"sync"
+)
+
+// AddressInfo is the information of an address of the wallet
+type AddressInfo struct {
+	// Index is the position of the address in the wallet, by derivation order
+	Index    int    `json:"index"`
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Method   string `json:"method"`
+	Role     string `json:"role"`
+	Archived bool   `json:"archived,omitempty"`
+}
+
+// AddressBalance is the balance and stake of an address
+type AddressBalance struct {
+	Address string
+	Balance Amount
+	Stake   Amount
+}
+
+// maxBalanceQueries is the number of the balance queries that are sent
+// to the server at the same time
+const maxBalanceQueries = 8
+
+// SetLabel changes the label of an address of the wallet
+func (w *Wallet) SetLabel(addr, label string) error {
+	if err := w.store.SetLabel(addr, strings.TrimSpace(label)); err != nil {
+		return err
+	}
+	return w.saveToFile()
+}
+
+// Archive hides an address from the address listings.
+// The key of the address is kept and it can still be used.
+func (w *Wallet) Archive(addr string) error {
+	if err := w.store.SetArchived(addr, true); err != nil {
+		return err
+	}
+	return w.saveToFile()
+}
+
+// Unarchive shows an archived address in the address listings again
+func (w *Wallet) Unarchive(addr string) error {
+	if err := w.store.SetArchived(addr, false); err != nil {
+		return err
+	}
+	return w.saveToFile()
+}
+
+// IsArchived checks if the address of the wallet is archived
+func (w *Wallet) IsArchived(addr string) bool {
+	return w.store.IsArchived(addr)
+}
+
+// AddressInfos returns the addresses of the wallet, ordered by derivation order
+func (w *Wallet) AddressInfos() []AddressInfo {
+	return w.store.AddressInfos()
+}
+
+// PublicKeys returns the public keys of the addresses of the wallet
+func (w *Wallet) PublicKeys(passphrase string) (map[string]string, error) {
+	return w.store.PublicKeys(passphrase)
+}
+
+// GetBalances fetches the balance and stake of the addresses concurrently.
+// The result is in the same order as the addresses.
+func (w *Wallet) GetBalances(addrs []string) ([]AddressBalance, error) {
+	balances := make([]AddressBalance, len(addrs))
+	errs := make([]error, len(addrs))
+
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, maxBalanceQueries)
+	for i, addr := range addrs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, addr string) {
+			defer func() { <-sem; wg.Done() }()
+
+			balance, stake, err := w.GetBalance(addr)
+			balances[i] = AddressBalance{Address: addr, Balance: balance, Stake: stake}
+			errs[i] = err
+		}(i, addr)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+)
+
+func TestSetLabel(t *testing.T) {
+	setup(t)
+
+	_, prv := bls.GenerateTestKeyPair()
+	assert.NoError(t, tWallet.ImportPrivateKey(tPassphrase, prv.String()))
+	addr := prv.PublicKey().Address().String()
+	assert.Empty(t, tWallet.Label(addr))
+
+	t.Run("Unknown address", func(t *testing.T) {
+		err := tWallet.SetLabel(crypto.GenerateTestAddress().String(), "foo")
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		assert.NoError(t, tWallet.SetLabel(addr, " imported "))
+		reopenWallet(t)
+		assert.Equal(t, "imported", tWallet.Label(addr))
+		assert.Equal(t, tWallet.store.VaultCRC, tWallet.store.calcVaultCRC())
+	})
+}
+
+func TestArchive(t *testing.T) {
+	setup(t)
+
+	var addr string
+	for a := range tWallet.Addresses() {
+		addr = a
+		break
+	}
+	crc := tWallet.store.VaultCRC
+	prv1, err := tWallet.PrivateKey(tPassphrase, addr)
+	assert.NoError(t, err)
+
+	t.Run("Unknown address", func(t *testing.T) {
+		err := tWallet.Archive(crypto.GenerateTestAddress().String())
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+		assert.False(t, tWallet.IsArchived(crypto.GenerateTestAddress().String()))
+	})
+
+	t.Run("Archive keeps the key", func(t *testing.T) {
+		assert.NoError(t, tWallet.Archive(addr))
+		reopenWallet(t)
+
+		assert.True(t, tWallet.IsArchived(addr))
+		assert.True(t, tWallet.Contains(addr))
+		prv2, err := tWallet.PrivateKey(tPassphrase, addr)
+		assert.NoError(t, err)
+		assert.Equal(t, prv1, prv2)
+	})
+
+	t.Run("Unarchive restores the vault", func(t *testing.T) {
+		assert.NoError(t, tWallet.Unarchive(addr))
+		reopenWallet(t)
+
+		assert.False(t, tWallet.IsArchived(addr))
+		assert.Equal(t, crc, tWallet.store.VaultCRC)
+	})
+}
+
+func TestAddressInfos(t *testing.T) {
+	setup(t)
+
+	valAddr, err := tWallet.NewValidatorAddress(tPassphrase, "val")
+	assert.NoError(t, err)
+	_, prv := bls.GenerateTestKeyPair()
+	assert.NoError(t, tWallet.ImportPrivateKey(tPassphrase, prv.String()))
+	impAddr := prv.PublicKey().Address().String()
+
+	infos := tWallet.AddressInfos()
+	assert.Len(t, infos, 5)
+	for i, info := range infos {
+		assert.Equal(t, i, info.Index)
+	}
+	assert.Equal(t, "addr-1", infos[0].Label)
+	assert.Equal(t, "addr-2", infos[1].Label)
+	assert.Equal(t, "addr-3", infos[2].Label)
+	assert.Equal(t, "KDF-CHAIN", infos[0].Method)
+	assert.Equal(t, RoleAccount, infos[0].Role)
+	assert.Equal(t, valAddr, infos[3].Address)
+	assert.Equal(t, RoleValidator, infos[3].Role)
+	assert.Equal(t, impAddr, infos[4].Address)
+	assert.Equal(t, "IMPORTED", infos[4].Method)
+
+	t.Run("Public keys", func(t *testing.T) {
+		pubs, err := tWallet.PublicKeys(tPassphrase)
+		assert.NoError(t, err)
+		assert.Len(t, pubs, 5)
+		for _, info := range infos {
+			pub, err := tWallet.PublicKey(tPassphrase, info.Address)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, pubs[info.Address])
+		}
+	})
+}
+
+func TestGetBalances(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	infos := tWallet.AddressInfos()
+	addrs := []string{}
+	for i, info := range infos {
+		addr, _ := crypto.AddressFromString(info.Address)
+		server.addAccount(addr, 0, int64(i+1)*UnitsPerCoin)
+		addrs = append(addrs, info.Address)
+	}
+	val, _ := crypto.AddressFromString(addrs[0])
+	server.addValidator(val, 0, 5*UnitsPerCoin, 0)
+
+	balances, err := tWallet.GetBalances(addrs)
+	assert.NoError(t, err)
+	assert.Len(t, balances, len(addrs))
+	for i, b := range balances {
+		assert.Equal(t, addrs[i], b.Address)
+		assert.Equal(t, Amount(int64(i+1)*UnitsPerCoin), b.Balance)
+	}
+	assert.Equal(t, Amount(5*UnitsPerCoin), balances[0].Stake)
+	assert.Zero(t, balances[1].Stake)
+
+	t.Run("Unknown account", func(t *testing.T) {
+		balances, err := tWallet.GetBalances([]string{crypto.GenerateTestAddress().String()})
+		assert.NoError(t, err)
+		assert.Zero(t, balances[0].Balance)
+		assert.Zero(t, balances[0].Stake)
+	})
+
+	t.Run("Node is not available", func(t *testing.T) {
+		client, err := MewGRPCClient("127.0.0.1:1")
+		assert.NoError(t, err)
+		tWallet.client = client
+
+		_, err = tWallet.GetBalances(addrs)
+		assert.Error(t, err)
+	})
+}
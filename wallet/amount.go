@@ -0,0 +1,142 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CoinSymbol is the symbol of the Zarb coin
+	CoinSymbol = "ZRB"
+	// AmountDecimals is the number of decimal places of one coin
+	AmountDecimals = 9
+	// UnitsPerCoin is the number of base units in one coin
+	UnitsPerCoin = 1000000000
+	// MaxAmount is the maximum amount in base units, defined by the protocol
+	MaxAmount = Amount(21 * 1e14)
+)
+
+var (
+	// ErrInvalidAmount describes an error in which the amount can't be parsed
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// Amount is an amount of coins in base units
+type Amount int64
+
+// ParseAmount parses an amount in coin units, like "1.5" or "1.5 ZRB".
+func ParseAmount(str string) (Amount, error) {
+	str = strings.TrimSpace(str)
+	str = strings.TrimSpace(strings.TrimSuffix(strings.ToUpper(str), CoinSymbol))
+
+	intPart, fracPart := str, ""
+	if i := strings.Index(str, "."); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("%w: empty amount", ErrInvalidAmount)
+	}
+	if len(fracPart) > AmountDecimals {
+		return 0, fmt.Errorf("%w: maximum %v decimal places are allowed", ErrInvalidAmount, AmountDecimals)
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, str)
+	}
+
+	coins := int64(0)
+	if intPart != "" {
+		var err error
+		coins, err = strconv.ParseInt(intPart, 10, 64)
+		if err != nil || coins > int64(MaxAmount/UnitsPerCoin) {
+			return 0, fmt.Errorf("%w: %s is more than the total supply, use raw units for base units", ErrInvalidAmount, str)
+		}
+	}
+	units := int64(0)
+	if fracPart != "" {
+		fracPart += strings.Repeat("0", AmountDecimals-len(fracPart))
+		units, _ = strconv.ParseInt(fracPart, 10, 64)
+	}
+
+	return checkAmount(Amount(coins*UnitsPerCoin + units))
+}
+
+// ParseRawAmount parses an amount in base units, like "1500000000".
+func ParseRawAmount(str string) (Amount, error) {
+	str = strings.TrimSpace(str)
+	if !isDigits(str) || str == "" {
+		return 0, fmt.Errorf("%w: %s, raw amounts should be integers", ErrInvalidAmount, str)
+	}
+	units, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+	return checkAmount(Amount(units))
+}
+
+func checkAmount(amt Amount) (Amount, error) {
+	if amt > MaxAmount {
+		return 0, fmt.Errorf("%w: %v is more than the total supply", ErrInvalidAmount, amt)
+	}
+	return amt, nil
+}
+
+func isDigits(str string) bool {
+	for _, r := range str {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Coins formats the amount in coin units, without trailing zeros, like "1.5"
+func (a Amount) Coins() string {
+	sign := ""
+	units := int64(a)
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	str := fmt.Sprintf("%s%d", sign, units/UnitsPerCoin)
+	frac := strings.TrimRight(fmt.Sprintf("%09d", units%UnitsPerCoin), "0")
+	if frac != "" {
+		str += "." + frac
+	}
+	return str
+}
+
+// String formats the amount in coin units with the coin symbol, like "1.5 ZRB"
+func (a Amount) String() string {
+	return a.Coins() + " " + CoinSymbol
+}
+
+// Format formats the amount in coin units, or in base units if raw is set
+func (a Amount) Format(raw bool) string {
+	if raw {
+		return strconv.FormatInt(int64(a), 10)
+	}
+	return a.String()
+}
+
+// SetRawUnits sets whether the amounts are given in base units, instead of coin units
+func (w *Wallet) SetRawUnits(raw bool) {
+	w.rawUnits = raw
+}
+
+// ParseAmount parses an amount based on the wallet units
+func (w *Wallet) ParseAmount(str string) (Amount, error) {
+	if w.rawUnits {
+		return ParseRawAmount(str)
+	}
+	return ParseAmount(str)
+}
+
+// FormatAmount formats the amount based on the wallet units, so ParseAmount parses it back
+func (w *Wallet) FormatAmount(amt Amount) string {
+	if w.rawUnits {
+		return strconv.FormatInt(int64(amt), 10)
+	}
+	return amt.Coins()
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		str    string
+		amount Amount
+	}{
+		{"0", 0},
+		{"1", 1000000000},
+		{"1.5", 1500000000},
+		{"1.5 ZRB", 1500000000},
+		{"1.5zrb", 1500000000},
+		{" 0.000000001 ", 1},
+		{".25", 250000000},
+		{"2.", 2000000000},
+		{"2100000", MaxAmount},
+	}
+	for _, test := range tests {
+		amount, err := ParseAmount(test.str)
+		assert.NoError(t, err, test.str)
+		assert.Equal(t, test.amount, amount, test.str)
+	}
+
+	invalids := []string{
+		"", ".", "-1", "1,5", "1.5 BTC", "0.0000000001", "1e9",
+		"1500000000", // more than total supply, it should be in raw units
+		"2100000.000000001",
+	}
+	for _, str := range invalids {
+		_, err := ParseAmount(str)
+		assert.ErrorIs(t, err, ErrInvalidAmount, str)
+	}
+}
+
+func TestParseRawAmount(t *testing.T) {
+	amount, err := ParseRawAmount("1500000000")
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(1500000000), amount)
+
+	for _, str := range []string{"", "1.5", "-1", "1 ZRB", "2100000000000001"} {
+		_, err := ParseRawAmount(str)
+		assert.ErrorIs(t, err, ErrInvalidAmount, str)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		coins  string
+	}{
+		{0, "0"},
+		{1, "0.000000001"},
+		{1500000000, "1.5"},
+		{2000000000, "2"},
+		{-1500000000, "-1.5"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.coins, test.amount.Coins())
+		assert.Equal(t, test.coins+" ZRB", test.amount.String())
+
+		parsed, err := ParseAmount(test.amount.Coins())
+		if test.amount >= 0 {
+			assert.NoError(t, err)
+			assert.Equal(t, test.amount, parsed)
+		}
+	}
+	assert.Equal(t, "1500000000", Amount(1500000000).Format(true))
+	assert.Equal(t, "1.5 ZRB", Amount(1500000000).Format(false))
+}
+
+func TestWalletFormatAmount(t *testing.T) {
+	w := &Wallet{}
+	for _, raw := range []bool{false, true} {
+		w.SetRawUnits(raw)
+		parsed, err := w.ParseAmount(w.FormatAmount(1500000001))
+		assert.NoError(t, err)
+		assert.Equal(t, Amount(1500000001), parsed)
+	}
+}
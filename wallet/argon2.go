@@ -3,7 +3,6 @@ package wallet
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -72,7 +71,7 @@ func (e *argon2Encrypter) decrypt(ct encrypted) (string, error) {
 	// Using MAC to heck if the password is correct
 	// https: //en.wikipedia.org/wiki/Authenticated_encryption#Encrypt-then-MAC_(EtM)
 	if !safeCmp(mac, sha256MAC(cipherKey[16:32], d)) {
-		return "", errors.New("invalid checksum")
+		return "", ErrInvalidPassphrase
 	}
 
 	text := aesCrypt(d, salt, cipherKey)
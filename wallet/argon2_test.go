@@ -20,5 +20,5 @@ func TestEncryptDecrypt(t *testing.T) {
 
 	e2 := newArgon2Encrypter("invalid_password")
 	_, err = e2.decrypt(ct)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidPassphrase)
 }
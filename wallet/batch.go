@@ -0,0 +1,315 @@
+package wallet
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// BatchPayment is a payment in a batch file
+type BatchPayment struct {
+	Line   int    `json:"-"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	Memo   string `json:"memo,omitempty"`
+}
+
+// BatchResult is the result of sending a payment in a batch
+type BatchResult struct {
+	Line     int    `json:"line"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Amount   Amount `json:"amount"`
+	Sequence int32  `json:"sequence"`
+	TxID     string `json:"tx_id"`
+	Error    string `json:"error,omitempty"`
+}
+
+var reportHeader = []string{"line", "from", "to", "amount", "sequence", "tx_id", "error"}
+
+// ReadBatchFile reads the payments from a JSON or CSV file.
+// A JSON file contains an array of payments.
+// A CSV file has "from,to,amount[,memo]" in each line.
+// Empty lines, comments (#) and the header line are ignored.
+func ReadBatchFile(path string) ([]BatchPayment, error) {
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := []BatchPayment{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &payments); err != nil {
+			return nil, err
+		}
+		for i := range payments {
+			payments[i].Line = i + 1
+		}
+		return payments, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(string(data)))
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if len(record) < 3 || len(record) > 4 {
+			return nil, fmt.Errorf("line %v: expected from,to,amount[,memo]", line)
+		}
+		if strings.EqualFold(record[0], "from") {
+			continue
+		}
+		p := BatchPayment{Line: line, From: record[0], To: record[1], Amount: record[2]}
+		if len(record) == 4 {
+			p.Memo = record[3]
+		}
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
+
+// ReadBatchReport reads the results of a previous run.
+// It returns no result if the report doesn't exist.
+func ReadBatchReport(path string) ([]BatchResult, error) {
+	if !util.PathExists(path) {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	results := []BatchResult{}
+	for _, record := range records {
+		if len(record) != len(reportHeader) || record[0] == reportHeader[0] {
+			continue
+		}
+		line, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, err
+		}
+		amount, err := ParseRawAmount(record[3])
+		if err != nil {
+			return nil, err
+		}
+		seq, err := strconv.ParseInt(record[4], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, BatchResult{
+			Line:     line,
+			From:     record[1],
+			To:       record[2],
+			Amount:   amount,
+			Sequence: int32(seq),
+			TxID:     record[5],
+			Error:    record[6],
+		})
+	}
+	return results, nil
+}
+
+// AppendBatchReport appends a result to the report file
+func AppendBatchReport(path string, res BatchResult) error {
+	exists := util.PathExists(path)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if !exists {
+		if err := w.Write(reportHeader); err != nil {
+			return err
+		}
+	}
+	err = w.Write([]string{
+		strconv.Itoa(res.Line),
+		res.From,
+		res.To,
+		strconv.FormatInt(int64(res.Amount), 10),
+		strconv.FormatInt(int64(res.Sequence), 10),
+		res.TxID,
+		res.Error,
+	})
+	if err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// SendBatch signs and broadcasts the payments, waiting for the interval
+// between each broadcast. The private keys are decrypted once.
+// Sequences are allocated locally, one by one for each sender.
+// The payments that have a transaction ID in the previous results are
+// skipped, so it is safe to resume a batch after a partial failure.
+// If a payment fails, the next payments of the same sender are skipped,
+// to avoid gaps in the sequences.
+// The broadcasted transactions are kept as pending.
+// The onResult callback is called after each payment.
+func (w *Wallet) SendBatch(passphrase string, payments []BatchPayment, previous []BatchResult,
+	interval time.Duration, onResult func(BatchResult)) error {
+	if err := w.checkChain(); err != nil {
+		return err
+	}
+	done := make(map[int]bool)
+	lastSeq := make(map[string]int32)
+	for _, res := range previous {
+		if res.TxID != "" {
+			done[res.Line] = true
+			if res.Sequence > lastSeq[res.From] {
+				lastSeq[res.From] = res.Sequence
+			}
+		}
+	}
+
+	// Parsing and checking all the payments before sending any of them
+	type batchTx struct {
+		payment  BatchPayment
+		sender   crypto.Address
+		receiver crypto.Address
+		amount   int64
+		fee      int64
+	}
+	txs := []batchTx{}
+	required := make(map[crypto.Address]int64)
+	for _, p := range payments {
+		if done[p.Line] {
+			continue
+		}
+		if err := checkMemo(p.Memo); err != nil {
+			return fmt.Errorf("line %v: %w", p.Line, err)
+		}
+		sender, err := crypto.AddressFromString(p.From)
+		if err != nil {
+			return fmt.Errorf("line %v: %w", p.Line, err)
+		}
+		receiver, err := crypto.AddressFromString(p.To)
+		if err != nil {
+			return fmt.Errorf("line %v: %w: %v", p.Line, ErrInvalidReceiver, err)
+		}
+		amount, err := w.ParseAmount(p.Amount)
+		if err != nil {
+			return fmt.Errorf("line %v: %w", p.Line, err)
+		}
+		if !w.store.Contains(sender) {
+			return fmt.Errorf("line %v: %w", p.Line, ErrAddressNotFound)
+		}
+		fee := w.CalcFee(int64(amount))
+		required[sender] += int64(amount) + fee
+
+		txs = append(txs, batchTx{payment: p, sender: sender, receiver: receiver, amount: int64(amount), fee: fee})
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+
+	signers := make(map[crypto.Address]crypto.Signer)
+	nextSeq := make(map[crypto.Address]int32)
+	for sender, amount := range required {
+		acc, err := w.client.GetAccount(sender)
+		if err != nil {
+			return notFoundError(err, ErrAccountNotFound)
+		}
+		nextSeq[sender], err = w.nextSequence(sender, false)
+		if err != nil {
+			return err
+		}
+		balance := acc.Balance - w.pendingValue(sender, false, nextSeq[sender])
+		if balance < amount {
+			return fmt.Errorf("%w, %v balance: %v, required: %v", ErrInsufficientFunds, sender, balance, amount)
+		}
+		if seq, ok := lastSeq[sender.String()]; ok && seq >= nextSeq[sender] {
+			// The previous transactions are not committed yet
+			nextSeq[sender] = seq + 1
+		}
+		prv, err := w.store.PrivateKey(passphrase, sender.String())
+		if err != nil {
+			return err
+		}
+		signers[sender] = crypto.NewSigner(prv)
+	}
+
+	info, err := w.client.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	lastHash, err := hash.FromBytes(info.LastBlockHash)
+	if err != nil {
+		return err
+	}
+	stamp := lastHash.Stamp()
+
+	failed := make(map[crypto.Address]bool)
+	for i, btx := range txs {
+		p := btx.payment
+		res := BatchResult{
+			Line:   p.Line,
+			From:   p.From,
+			To:     p.To,
+			Amount: Amount(btx.amount),
+		}
+		if failed[btx.sender] {
+			res.Error = "skipped, a previous transaction of the sender failed"
+			onResult(res)
+			continue
+		}
+
+		trx := tx.NewSendTx(stamp, nextSeq[btx.sender], btx.sender, btx.receiver, btx.amount, btx.fee, p.Memo)
+		signers[btx.sender].SignMsg(trx)
+		res.Sequence = trx.Sequence()
+
+		bs, err := trx.Bytes()
+		if err == nil {
+			res.TxID, err = w.client.SendTx(bs)
+		}
+		if err != nil {
+			res.Error = err.Error()
+			failed[btx.sender] = true
+		} else {
+			nextSeq[btx.sender]++
+			w.addPending(trx, bs, info.LastBlockHeight)
+		}
+		onResult(res)
+
+		if i < len(txs)-1 {
+			time.Sleep(interval)
+		}
+	}
+
+	if err := w.saveToFile(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return errors.New("some payments failed, check the report and run the batch again")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/tx/payload"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+func TestReadBatchFile(t *testing.T) {
+	addr1 := crypto.GenerateTestAddress().String()
+	addr2 := crypto.GenerateTestAddress().String()
+
+	t.Run("CSV", func(t *testing.T) {
+		path := util.TempFilePath() + ".csv"
+		data := fmt.Sprintf("from,to,amount,memo\n# comment\n%s,%s,1.5\n\n%s,%s,2,\"memo, with comma\"\n",
+			addr1, addr2, addr1, addr2)
+		assert.NoError(t, util.WriteFile(path, []byte(data)))
+
+		payments, err := ReadBatchFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []BatchPayment{
+			{Line: 3, From: addr1, To: addr2, Amount: "1.5"},
+			{Line: 5, From: addr1, To: addr2, Amount: "2", Memo: "memo, with comma"},
+		}, payments)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		path := util.TempFilePath() + ".json"
+		data := fmt.Sprintf(`[{"from":"%s","to":"%s","amount":"1.5","memo":"m"}]`, addr1, addr2)
+		assert.NoError(t, util.WriteFile(path, []byte(data)))
+
+		payments, err := ReadBatchFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []BatchPayment{{Line: 1, From: addr1, To: addr2, Amount: "1.5", Memo: "m"}}, payments)
+	})
+
+	t.Run("Invalid CSV", func(t *testing.T) {
+		path := util.TempFilePath() + ".csv"
+		assert.NoError(t, util.WriteFile(path, []byte("a,b\n")))
+		_, err := ReadBatchFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestBatchReport(t *testing.T) {
+	path := util.TempFilePath()
+	results, err := ReadBatchReport(path)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	res1 := BatchResult{Line: 1, From: "a", To: "b", Amount: 10, Sequence: 2, TxID: "id"}
+	res2 := BatchResult{Line: 2, From: "a", To: "c", Amount: 20, Error: "failed, \"quoted\""}
+	assert.NoError(t, AppendBatchReport(path, res1))
+	assert.NoError(t, AppendBatchReport(path, res2))
+
+	results, err = ReadBatchReport(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []BatchResult{res1, res2}, results)
+}
+
+func TestSendBatch(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+
+	payments := []BatchPayment{}
+	for i := 1; i <= 4; i++ {
+		payments = append(payments, BatchPayment{
+			Line:   i,
+			From:   sender.String(),
+			To:     crypto.GenerateTestAddress().String(),
+			Amount: "1.5",
+		})
+	}
+
+	// The third payment fails
+	results := []BatchResult{}
+	err := tWallet.SendBatch(tPassphrase, payments, nil, 0, func(res BatchResult) {
+		results = append(results, res)
+		if len(results) == 2 {
+			server.sendErr = errors.New("connection lost")
+		}
+	})
+	assert.Error(t, err)
+	assert.Len(t, results, 4)
+	assert.Len(t, server.sent, 2)
+	assert.Equal(t, int32(11), server.sent[0].Sequence())
+	assert.Equal(t, int32(12), server.sent[1].Sequence())
+	assert.NotEmpty(t, results[1].TxID)
+	assert.Empty(t, results[2].TxID)
+	assert.Contains(t, results[3].Error, "skipped")
+	assert.Equal(t, int64(1500000000), server.sent[0].Payload().(*payload.SendPayload).Amount)
+
+	// Resuming, the previous transactions are not committed yet
+	server.sendErr = nil
+	resumed := []BatchResult{}
+	err = tWallet.SendBatch(tPassphrase, payments, results, 0, func(res BatchResult) {
+		resumed = append(resumed, res)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resumed, 2)
+	assert.Equal(t, 3, resumed[0].Line)
+	assert.Equal(t, int32(13), resumed[0].Sequence)
+	assert.Equal(t, int32(14), resumed[1].Sequence)
+	assert.Len(t, server.sent, 4)
+
+	t.Run("Insufficient funds", func(t *testing.T) {
+		server.addAccount(sender, 14, UnitsPerCoin)
+		err := tWallet.SendBatch(tPassphrase, payments, nil, 0, func(res BatchResult) {})
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.Len(t, server.sent, 4)
+	})
+}
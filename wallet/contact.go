@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/crypto"
+)
+
+// Contact is an external address in the address book of the wallet
+type Contact struct {
+	Name    string   `json:"name"`
+	Address string   `json:"address"`
+	Note    string   `json:"note,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// HasTag returns true if the contact has the tag
+func (c Contact) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddContact adds a contact to the address book.
+// The name can be used instead of the address, so it should not be an address.
+func (w *Wallet) AddContact(c Contact) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("%w: contact name is empty", ErrInvalidReceiver)
+	}
+	if _, err := crypto.AddressFromString(c.Name); err == nil {
+		return fmt.Errorf("%w: contact name should not be an address", ErrInvalidReceiver)
+	}
+	addr, err := crypto.AddressFromString(c.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReceiver, err)
+	}
+	c.Address = addr.String()
+	if _, ok := w.Contact(c.Name); ok {
+		return fmt.Errorf("%w: %s", ErrContactExists, c.Name)
+	}
+	if other, ok := w.ContactByAddress(c.Address); ok {
+		return fmt.Errorf("%w: %s is saved as %s", ErrContactExists, c.Address, other.Name)
+	}
+
+	w.store.Contacts = append(w.store.Contacts, c)
+	return w.saveToFile()
+}
+
+// RemoveContact removes the contact from the address book
+func (w *Wallet) RemoveContact(name string) error {
+	for i, c := range w.store.Contacts {
+		if c.Name == name {
+			w.store.Contacts = append(w.store.Contacts[:i], w.store.Contacts[i+1:]...)
+			return w.saveToFile()
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrContactNotFound, name)
+}
+
+// Contacts returns the contacts of the address book, sorted by name
+func (w *Wallet) Contacts() []Contact {
+	contacts := append([]Contact{}, w.store.Contacts...)
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].Name < contacts[j].Name })
+	return contacts
+}
+
+// Contact returns the contact with the given name
+func (w *Wallet) Contact(name string) (Contact, bool) {
+	for _, c := range w.store.Contacts {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Contact{}, false
+}
+
+// ContactByAddress returns the contact with the given address
+func (w *Wallet) ContactByAddress(addrStr string) (Contact, bool) {
+	for _, c := range w.store.Contacts {
+		if c.Address == addrStr {
+			return c, true
+		}
+	}
+	return Contact{}, false
+}
+
+// ResolveAddress returns the address of a contact, or the address itself
+func (w *Wallet) ResolveAddress(nameOrAddr string) (string, error) {
+	if _, err := crypto.AddressFromString(nameOrAddr); err == nil {
+		return nameOrAddr, nil
+	}
+	if c, ok := w.Contact(nameOrAddr); ok {
+		return c.Address, nil
+	}
+	return "", fmt.Errorf("%w: %s is not an address or a contact", ErrInvalidReceiver, nameOrAddr)
+}
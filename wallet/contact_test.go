@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+)
+
+func TestContacts(t *testing.T) {
+	setup(t)
+
+	bob := crypto.GenerateTestAddress().String()
+	carol := crypto.GenerateTestAddress().String()
+	assert.NoError(t, tWallet.AddContact(Contact{Name: "bob", Address: bob, Note: "exchange", Tags: []string{"Work"}}))
+	assert.NoError(t, tWallet.AddContact(Contact{Name: " alice ", Address: carol}))
+
+	t.Run("Invalid contacts", func(t *testing.T) {
+		assert.ErrorIs(t, tWallet.AddContact(Contact{Name: "", Address: bob}), ErrInvalidReceiver)
+		assert.ErrorIs(t, tWallet.AddContact(Contact{Name: carol, Address: carol}), ErrInvalidReceiver)
+		assert.ErrorIs(t, tWallet.AddContact(Contact{Name: "dave", Address: "invalid"}), ErrInvalidReceiver)
+		assert.ErrorIs(t, tWallet.AddContact(Contact{Name: "bob", Address: crypto.GenerateTestAddress().String()}), ErrContactExists)
+		assert.ErrorIs(t, tWallet.AddContact(Contact{Name: "dave", Address: bob}), ErrContactExists)
+	})
+
+	t.Run("Contacts are saved", func(t *testing.T) {
+		reopenWallet(t)
+		contacts := tWallet.Contacts()
+		assert.Len(t, contacts, 2)
+		assert.Equal(t, "alice", contacts[0].Name)
+		assert.Equal(t, "bob", contacts[1].Name)
+		assert.Equal(t, "exchange", contacts[1].Note)
+		assert.True(t, contacts[1].HasTag("work"))
+		assert.False(t, contacts[0].HasTag("work"))
+
+		// Contacts are not part of the vault
+		assert.Equal(t, tWallet.store.VaultCRC, tWallet.store.calcVaultCRC())
+	})
+
+	t.Run("Resolve address", func(t *testing.T) {
+		addr, err := tWallet.ResolveAddress("bob")
+		assert.NoError(t, err)
+		assert.Equal(t, bob, addr)
+
+		addr, err = tWallet.ResolveAddress(carol)
+		assert.NoError(t, err)
+		assert.Equal(t, carol, addr)
+
+		_, err = tWallet.ResolveAddress("dave")
+		assert.ErrorIs(t, err, ErrInvalidReceiver)
+
+		c, ok := tWallet.ContactByAddress(bob)
+		assert.True(t, ok)
+		assert.Equal(t, "bob", c.Name)
+	})
+
+	t.Run("Remove contact", func(t *testing.T) {
+		assert.ErrorIs(t, tWallet.RemoveContact("dave"), ErrContactNotFound)
+		assert.NoError(t, tWallet.RemoveContact("bob"))
+		_, ok := tWallet.Contact("bob")
+		assert.False(t, ok)
+		assert.Len(t, tWallet.Contacts(), 1)
+	})
+}
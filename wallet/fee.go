@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/param"
+)
+
+// FeePriority defines how fast a transaction should be committed
+type FeePriority int
+
+const (
+	FeePriorityLow    = FeePriority(0)
+	FeePriorityNormal = FeePriority(1)
+	FeePriorityHigh   = FeePriority(2)
+)
+
+func (p FeePriority) String() string {
+	switch p {
+	case FeePriorityLow:
+		return "low"
+	case FeePriorityNormal:
+		return "normal"
+	case FeePriorityHigh:
+		return "high"
+	}
+	return fmt.Sprintf("%d", p)
+}
+
+// factor returns the fee multiplier of the priority
+func (p FeePriority) factor() float64 {
+	switch p {
+	case FeePriorityLow:
+		return 0.8
+	case FeePriorityHigh:
+		return 1.5
+	}
+	return 1
+}
+
+// FeePriorityFromString parses the fee priority: low, normal or high
+func FeePriorityFromString(str string) (FeePriority, error) {
+	switch strings.ToLower(str) {
+	case "low":
+		return FeePriorityLow, nil
+	case "", "normal":
+		return FeePriorityNormal, nil
+	case "high":
+		return FeePriorityHigh, nil
+	}
+	return FeePriorityNormal, fmt.Errorf("invalid fee priority: %s", str)
+}
+
+// FeePolicy defines how the transaction fee is calculated
+type FeePolicy struct {
+	Fraction float64 `json:"fraction"`
+	MinFee   int64   `json:"min_fee"`
+	MaxFee   int64   `json:"max_fee"` // Zero means no limit
+	// Fixed means the consensus accepts exactly the calculated fee,
+	// and the priority has no effect.
+	Fixed bool `json:"fixed"`
+}
+
+// feePolicyFromParams creates a fee policy from the consensus parameters.
+// In this version of the protocol, the fee should be exactly:
+//
+//	max(amount × fee_fraction, minimum_fee)
+func feePolicyFromParams(params param.Params) FeePolicy {
+	return FeePolicy{
+		Fraction: params.FeeFraction,
+		MinFee:   params.MinimumFee,
+		Fixed:    true,
+	}
+}
+
+// CalcFee calculates the fee of the given amount
+func (p FeePolicy) CalcFee(amount int64, priority FeePriority) int64 {
+	fee := int64(float64(amount) * p.Fraction)
+	if !p.Fixed {
+		fee = int64(float64(fee) * priority.factor())
+	}
+	if fee < p.MinFee {
+		fee = p.MinFee
+	}
+	if p.MaxFee > 0 && fee > p.MaxFee {
+		fee = p.MaxFee
+	}
+	return fee
+}
+
+// Explain describes how the fee of the given amount is calculated
+func (p FeePolicy) Explain(amount int64, priority FeePriority) string {
+	fee := p.CalcFee(amount, priority)
+	desc := fmt.Sprintf("%v × %v", amount, p.Fraction)
+	if !p.Fixed {
+		desc = fmt.Sprintf("%s × %v (%s priority)", desc, priority.factor(), priority)
+	}
+	desc = fmt.Sprintf("max(%s, %v)", desc, p.MinFee)
+	if p.MaxFee > 0 {
+		desc = fmt.Sprintf("min(%s, %v)", desc, p.MaxFee)
+	}
+	desc = fmt.Sprintf("fee = %s = %v", desc, fee)
+	if p.Fixed && priority != FeePriorityNormal {
+		desc += ", the fee is fixed by consensus and the priority is ignored"
+	}
+	return desc
+}
+
+// FeePolicy returns the fee policy of the wallet network, derived from the
+// consensus parameters of the network (see Network.params).
+// The policy is cached for the wallet's lifetime.
+func (w *Wallet) FeePolicy() FeePolicy {
+	if w.feePolicy == nil {
+		policy := feePolicyFromParams(w.params())
+		w.feePolicy = &policy
+	}
+	return *w.feePolicy
+}
+
+// SetFeePriority sets the fee priority for the new transactions
+func (w *Wallet) SetFeePriority(priority FeePriority) {
+	w.feePriority = priority
+}
+
+// CalcFee calculates the fee of the given amount
+func (w *Wallet) CalcFee(amount int64) int64 {
+	return w.FeePolicy().CalcFee(amount, w.feePriority)
+}
+
+// ExplainFee describes how the fee of the given amount is calculated
+func (w *Wallet) ExplainFee(amount int64) string {
+	return w.FeePolicy().Explain(amount, w.feePriority)
+}
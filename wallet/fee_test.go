@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/genesis"
+	"github.com/zarbchain/zarb-go/param"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+func TestFeePriorityFromString(t *testing.T) {
+	tests := []struct {
+		str      string
+		priority FeePriority
+	}{
+		{"", FeePriorityNormal},
+		{"low", FeePriorityLow},
+		{"Normal", FeePriorityNormal},
+		{"HIGH", FeePriorityHigh},
+	}
+	for _, test := range tests {
+		priority, err := FeePriorityFromString(test.str)
+		assert.NoError(t, err)
+		assert.Equal(t, test.priority, priority)
+	}
+
+	_, err := FeePriorityFromString("urgent")
+	assert.Error(t, err)
+}
+
+func TestCalcFee(t *testing.T) {
+	t.Run("Consensus policy", func(t *testing.T) {
+		policy := feePolicyFromParams(param.DefaultParams())
+		tests := []struct {
+			amount int64
+			fee    int64
+		}{
+			{0, 1000},
+			{1000, 1000},
+			{1000000, 1000},
+			{2000000, 2000},
+			{123456789, 123456},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.fee, policy.CalcFee(test.amount, FeePriorityNormal))
+			assert.Equal(t, test.fee, policy.CalcFee(test.amount, FeePriorityHigh), "priority should be ignored")
+		}
+		assert.Contains(t, policy.Explain(2000000, FeePriorityHigh), "= 2000")
+	})
+
+	t.Run("Testnet policy", func(t *testing.T) {
+		w, err := CreateWallet(util.TempFilePath(), "", int(Testnet))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10000), w.CalcFee(0))
+		assert.Equal(t, int64(10000), w.CalcFee(100000000))
+		assert.Equal(t, int64(12345), w.CalcFee(123456789))
+		assert.Equal(t, genesis.Testnet().Params().FeeFraction, w.FeePolicy().Fraction)
+	})
+
+	t.Run("Flexible policy", func(t *testing.T) {
+		policy := FeePolicy{Fraction: 0.001, MinFee: 1000, MaxFee: 5000}
+		assert.Equal(t, int64(1000), policy.CalcFee(1000000, FeePriorityLow))
+		assert.Equal(t, int64(2000), policy.CalcFee(2000000, FeePriorityNormal))
+		assert.Equal(t, int64(3000), policy.CalcFee(2000000, FeePriorityHigh))
+		assert.Equal(t, int64(5000), policy.CalcFee(9000000, FeePriorityHigh))
+		assert.Contains(t, policy.Explain(9000000, FeePriorityHigh), "min(")
+	})
+}
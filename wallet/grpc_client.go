@@ -3,11 +3,15 @@ package wallet
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/zarbchain/zarb-go/crypto"
 	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
 	zarb "github.com/zarbchain/zarb-go/www/grpc/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type GrpcClient struct {
@@ -25,6 +29,28 @@ func MewGRPCClient(rpcEndpoint string) (*GrpcClient, error) {
 	}, nil
 }
 
+// noServer is a connection that fails all the calls with the error.
+// It is used for the networks that have no known server, so the wallet can
+// still be used offline.
+type noServer struct {
+	err error
+}
+
+func (n noServer) Invoke(context.Context, string, interface{}, interface{}, ...grpc.CallOption) error {
+	return n.err
+}
+
+func (n noServer) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, n.err
+}
+
+// newNoServerClient creates a client that fails all the calls as unavailable
+func newNoServerClient(msg string) *GrpcClient {
+	return &GrpcClient{
+		client: zarb.NewZarbClient(noServer{err: status.Error(codes.Unavailable, msg)}),
+	}
+}
+
 func (c *GrpcClient) GetStamp() (hash.Stamp, error) {
 	info, err := c.client.GetBlockchainInfo(context.Background(), &zarb.BlockchainInfoRequest{})
 	if err != nil {
@@ -34,40 +60,94 @@ func (c *GrpcClient) GetStamp() (hash.Stamp, error) {
 	return h.Stamp(), nil
 }
 
+func (c *GrpcClient) GetBlockchainInfo() (*zarb.BlockchainInfoResponse, error) {
+	return c.client.GetBlockchainInfo(context.Background(), &zarb.BlockchainInfoRequest{})
+}
+
+func (c *GrpcClient) GetAccount(addr crypto.Address) (*zarb.AccountInfo, error) {
+	res, err := c.client.GetAccount(context.Background(), &zarb.AccountRequest{Address: addr.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Account, nil
+}
+
+func (c *GrpcClient) GetValidator(addr crypto.Address) (*zarb.ValidatorInfo, error) {
+	res, err := c.client.GetValidator(context.Background(), &zarb.ValidatorRequest{Address: addr.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Validator, nil
+}
+
+// isNotFound checks if the node doesn't have the account or the validator.
+// The node returns "invalid argument" for the accounts that don't exist,
+// and the address is already validated, so it means "not found" too.
+func isNotFound(err error) bool {
+	code := status.Code(err)
+	return code == codes.NotFound || code == codes.InvalidArgument
+}
+
+// notFoundError wraps the error of the node with the not-found error, if the
+// node doesn't have the account or the validator. The other errors, like the
+// network failures, are returned as they are.
+func notFoundError(err, notFound error) error {
+	if isNotFound(err) {
+		return fmt.Errorf("%w: %v", notFound, err)
+	}
+	return err
+}
+
 func (c *GrpcClient) GetAccountBalance(addr crypto.Address) (int64, error) {
-	acc, err := c.client.GetAccount(context.Background(), &zarb.AccountRequest{Address: addr.Bytes()})
+	acc, err := c.GetAccount(addr)
 	if err != nil {
 		return 0, err
 	}
 
-	return acc.Account.Balance, nil
+	return acc.Balance, nil
 }
 
 func (c *GrpcClient) GetAccountSequence(addr crypto.Address) (int32, error) {
-	acc, err := c.client.GetAccount(context.Background(), &zarb.AccountRequest{Address: addr.Bytes()})
+	acc, err := c.GetAccount(addr)
 	if err != nil {
 		return 0, err
 	}
 
-	return acc.Account.Sequence + 1, nil
+	return acc.Sequence + 1, nil
 }
 
 func (c *GrpcClient) GetValidatorSequence(addr crypto.Address) (int32, error) {
-	val, err := c.client.GetValidator(context.Background(), &zarb.ValidatorRequest{Address: addr.Bytes()})
+	val, err := c.GetValidator(addr)
 	if err != nil {
 		return 0, err
 	}
 
-	return val.Validator.Sequence + 1, nil
+	return val.Sequence + 1, nil
 }
 
 func (c *GrpcClient) GetValidatorStake(addr crypto.Address) (int64, error) {
-	val, err := c.client.GetValidator(context.Background(), &zarb.ValidatorRequest{Address: addr.Bytes()})
+	val, err := c.GetValidator(addr)
 	if err != nil {
 		return 0, err
 	}
 
-	return val.Validator.Stake, nil
+	return val.Stake, nil
+}
+
+func (c *GrpcClient) GetTransaction(id tx.ID) (*zarb.TransactionInfo, error) {
+	res, err := c.client.GetTransaction(context.Background(), &zarb.TransactionRequest{Id: id.String()})
+	if err != nil {
+		// The node only keeps committed transactions, and the ID is
+		// already validated, so an invalid argument means "not found".
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, ErrTxNotFound
+		}
+		return nil, err
+	}
+
+	return res.Tranaction, nil
 }
 
 func (c *GrpcClient) SendTx(payload []byte) (string, error) {
@@ -81,3 +161,11 @@ func (c *GrpcClient) SendTx(payload []byte) (string, error) {
 
 	return res.Id, nil
 }
+
+func (c *GrpcClient) GetBlockHash(height int32) (hash.Hash, error) {
+	res, err := c.client.GetBlockHash(context.Background(), &zarb.BlockHashRequest{Height: height})
+	if err != nil {
+		return hash.UndefHash, err
+	}
+	return hash.FromBytes(res.Hash)
+}
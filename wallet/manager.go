@@ -0,0 +1,252 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// DefaultWalletName is the name of the wallet that is used when no wallet is selected
+const DefaultWalletName = "default_wallet"
+
+// currentWalletFile keeps the name of the current wallet, in the wallets directory
+const currentWalletFile = ".current_wallet"
+
+// Manager manages the wallet files in the wallets directory.
+// The wallets are addressed by their file names.
+type Manager struct {
+	dir string
+}
+
+// WalletInfo is the summary of a wallet file
+type WalletInfo struct {
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	UUID         string    `json:"uuid"`
+	Network      int       `json:"network"`
+	Encrypted    bool      `json:"encrypted"`
+	AddressCount int       `json:"address_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	// Error is set if the wallet file can't be read, e.g. it is corrupted
+	Error string `json:"error,omitempty"`
+}
+
+// NewManager creates a manager for the wallets in the directory
+func NewManager(dir string) *Manager {
+	return &Manager{dir: dir}
+}
+
+// Path returns the path of the wallet with the given name
+func (m *Manager) Path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." ||
+		strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidWalletName, name)
+	}
+	return filepath.Join(m.dir, name), nil
+}
+
+// readStore reads the store of a wallet file, without opening the wallet
+func readStore(path string) (*Store, error) {
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := new(Store)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrNotWallet, path, err)
+	}
+	if s.Vault == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotWallet, path)
+	}
+	if s.VaultCRC != s.calcVaultCRC() {
+		return nil, ErrInvalidCRC
+	}
+	return s, nil
+}
+
+// Info returns the summary of the wallet with the given name
+func (m *Manager) Info(name string) (*WalletInfo, error) {
+	path, err := m.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	if !util.PathExists(path) {
+		return nil, fmt.Errorf("%w: %s", ErrWalletNotFound, name)
+	}
+	s, err := readStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletInfo{
+		Name:         name,
+		Path:         path,
+		UUID:         s.UUID.String(),
+		Network:      s.Network,
+		Encrypted:    s.Encrypted,
+		AddressCount: len(s.Vault.Addresses),
+		CreatedAt:    s.CreatedAt,
+	}, nil
+}
+
+// Wallets returns the wallets in the directory, sorted by name.
+// The files that are not wallets are skipped. The wallets that can't be read,
+// e.g. because of an invalid CRC, are returned with the error.
+func (m *Manager) Wallets() ([]WalletInfo, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return []WalletInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	infos := []WalletInfo{}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, err := m.Info(e.Name())
+		if errors.Is(err, ErrNotWallet) {
+			continue
+		}
+		if err != nil {
+			path, _ := m.Path(e.Name())
+			info = &WalletInfo{Name: e.Name(), Path: path, Error: err.Error()}
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// TokensPath returns the default path of the API tokens file of the wallet
+func TokensPath(walletPath string) string {
+	return walletPath + "_tokens.json"
+}
+
+// StakeMovePath returns the default path of the state file of moving
+// the validator stake
+func StakeMovePath(walletPath, validator string) string {
+	return fmt.Sprintf("%s_stake_move_%s.json", walletPath, validator)
+}
+
+// auxFiles returns the names of the files that are kept next to the wallet,
+// like the API tokens and the stake move states.
+func (m *Manager) auxFiles(name string) ([]string, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+	tokens := filepath.Base(TokensPath(name))
+	stakePrefix := strings.TrimSuffix(filepath.Base(StakeMovePath(name, "")), ".json")
+	files := []string{}
+	for _, e := range entries {
+		n := e.Name()
+		if n == tokens || (strings.HasPrefix(n, stakePrefix) && strings.HasSuffix(n, ".json")) {
+			files = append(files, n)
+		}
+	}
+	return files, nil
+}
+
+// Current returns the name of the current wallet
+func (m *Manager) Current() string {
+	data, err := os.ReadFile(filepath.Join(m.dir, currentWalletFile))
+	if err != nil {
+		return DefaultWalletName
+	}
+	name := strings.TrimSpace(string(data))
+	if _, err := m.Path(name); err != nil {
+		return DefaultWalletName
+	}
+	return name
+}
+
+// CurrentPath returns the path of the current wallet
+func (m *Manager) CurrentPath() string {
+	return filepath.Join(m.dir, m.Current())
+}
+
+func (m *Manager) setCurrent(name string) error {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.dir, currentWalletFile), []byte(name+"\n"), 0600)
+}
+
+// Use sets the current wallet
+func (m *Manager) Use(name string) error {
+	if _, err := m.Info(name); err != nil {
+		return err
+	}
+	return m.setCurrent(name)
+}
+
+// Rename renames the wallet and the files next to it.
+// If it is the current wallet, it remains current.
+func (m *Manager) Rename(oldName, newName string) error {
+	if _, err := m.Info(oldName); err != nil {
+		return err
+	}
+	oldPath, _ := m.Path(oldName)
+	newPath, err := m.Path(newName)
+	if err != nil {
+		return err
+	}
+	if util.PathExists(newPath) {
+		return ErrWalletExits
+	}
+	aux, err := m.auxFiles(oldName)
+	if err != nil {
+		return err
+	}
+	current := m.Current()
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+	for _, n := range aux {
+		newAux := newName + strings.TrimPrefix(n, oldName)
+		if err := os.Rename(filepath.Join(m.dir, n), filepath.Join(m.dir, newAux)); err != nil {
+			return err
+		}
+	}
+	if current == oldName {
+		return m.setCurrent(newName)
+	}
+	return nil
+}
+
+// Delete removes the wallet file and the files next to it.
+// If it is the current wallet, the default wallet becomes current.
+func (m *Manager) Delete(name string) error {
+	if _, err := m.Info(name); err != nil {
+		return err
+	}
+	path, _ := m.Path(name)
+	aux, err := m.auxFiles(name)
+	if err != nil {
+		return err
+	}
+	current := m.Current()
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	for _, n := range aux {
+		if err := os.Remove(filepath.Join(m.dir, n)); err != nil {
+			return err
+		}
+	}
+	if current == name {
+		err := os.Remove(filepath.Join(m.dir, currentWalletFile))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
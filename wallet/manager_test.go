@@ -0,0 +1,139 @@
+package wallet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupManager(t *testing.T) *Manager {
+	m := NewManager(t.TempDir())
+
+	for _, name := range []string{"alice", "bob"} {
+		path, err := m.Path(name)
+		require.NoError(t, err)
+		w, err := CreateWallet(path, "", 0)
+		require.NoError(t, err)
+		_, err = w.NewAddress("", "addr-1")
+		require.NoError(t, err)
+	}
+	// Not wallets
+	require.NoError(t, os.WriteFile(TokensPath(filepath.Join(m.dir, "alice")), []byte("[]"), 0600))
+	require.NoError(t, os.WriteFile(StakeMovePath(filepath.Join(m.dir, "bob"), "zc1abc"), []byte("{}"), 0600))
+	return m
+}
+
+func TestManagerWallets(t *testing.T) {
+	m := setupManager(t)
+
+	infos, err := m.Wallets()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "alice", infos[0].Name)
+	assert.Equal(t, "bob", infos[1].Name)
+	assert.Equal(t, 1, infos[0].AddressCount)
+	assert.False(t, infos[0].Encrypted)
+	assert.NotEqual(t, infos[0].UUID, infos[1].UUID)
+
+	t.Run("Invalid CRC", func(t *testing.T) {
+		path := filepath.Join(m.dir, "carol")
+		_, err := CreateWallet(path, "", 0)
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		store := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &store))
+		store["crc"] = 1
+		data, err = json.Marshal(store)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0600))
+
+		infos, err := m.Wallets()
+		require.NoError(t, err)
+		require.Len(t, infos, 3)
+		assert.Equal(t, "carol", infos[2].Name)
+		assert.Contains(t, infos[2].Error, ErrInvalidCRC.Error())
+		assert.Empty(t, infos[0].Error)
+	})
+
+	t.Run("No wallets directory", func(t *testing.T) {
+		infos, err := NewManager(filepath.Join(m.dir, "none")).Wallets()
+		assert.NoError(t, err)
+		assert.Empty(t, infos)
+	})
+}
+
+func TestManagerPath(t *testing.T) {
+	m := NewManager(t.TempDir())
+
+	for _, name := range []string{"", ".", "..", "../alice", "a/b", `a\b`, ".current_wallet"} {
+		_, err := m.Path(name)
+		assert.ErrorIs(t, err, ErrInvalidWalletName, name)
+	}
+	path, err := m.Path("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(m.dir, "alice"), path)
+}
+
+func TestManagerUse(t *testing.T) {
+	m := setupManager(t)
+	assert.Equal(t, DefaultWalletName, m.Current())
+
+	assert.ErrorIs(t, m.Use("carol"), ErrWalletNotFound)
+	assert.Error(t, m.Use("alice_tokens.json"))
+
+	assert.NoError(t, m.Use("bob"))
+	assert.Equal(t, "bob", m.Current())
+	assert.Equal(t, filepath.Join(m.dir, "bob"), m.CurrentPath())
+
+	// The setting is persisted
+	assert.Equal(t, "bob", NewManager(m.dir).Current())
+}
+
+func TestManagerRename(t *testing.T) {
+	m := setupManager(t)
+	require.NoError(t, m.Use("bob"))
+
+	assert.ErrorIs(t, m.Rename("bob", "alice"), ErrWalletExits)
+	assert.ErrorIs(t, m.Rename("carol", "dave"), ErrWalletNotFound)
+	assert.ErrorIs(t, m.Rename("bob", "../bob"), ErrInvalidWalletName)
+
+	assert.NoError(t, m.Rename("bob", "carol"))
+	assert.Equal(t, "carol", m.Current())
+	_, err := OpenWallet(filepath.Join(m.dir, "carol"))
+	assert.NoError(t, err)
+	assert.FileExists(t, StakeMovePath(filepath.Join(m.dir, "carol"), "zc1abc"))
+	assert.NoFileExists(t, StakeMovePath(filepath.Join(m.dir, "bob"), "zc1abc"))
+
+	assert.NoError(t, m.Rename("alice", "dave"))
+	assert.Equal(t, "carol", m.Current())
+	assert.FileExists(t, TokensPath(filepath.Join(m.dir, "dave")))
+	assert.NoFileExists(t, TokensPath(filepath.Join(m.dir, "alice")))
+}
+
+func TestManagerDelete(t *testing.T) {
+	m := setupManager(t)
+	require.NoError(t, m.Use("bob"))
+
+	assert.ErrorIs(t, m.Delete("carol"), ErrWalletNotFound)
+	assert.Error(t, m.Delete("alice_tokens.json"))
+
+	assert.NoError(t, m.Delete("alice"))
+	assert.Equal(t, "bob", m.Current())
+
+	assert.NoError(t, m.Delete("bob"))
+	assert.Equal(t, DefaultWalletName, m.Current())
+
+	infos, err := m.Wallets()
+	require.NoError(t, err)
+	assert.Empty(t, infos)
+
+	// The files next to the wallets are removed too
+	entries, err := os.ReadDir(m.dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
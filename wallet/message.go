@@ -0,0 +1,103 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/encoding"
+)
+
+// messagePrefix separates the signed messages from the transactions.
+// The sign bytes of a transaction start with its version, so a signed message
+// can't be a valid transaction.
+const messagePrefix = "Zarb Signed Message:\n"
+
+// SignedMessage is a message signed by a wallet address.
+// It contains the public key, so it can be verified without the blockchain.
+type SignedMessage struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// messageSignBytes returns the domain-separated bytes of the message to be signed
+func messageSignBytes(msg string) []byte {
+	buf := bytes.NewBufferString(messagePrefix)
+	_ = encoding.WriteVarString(buf, msg)
+	return buf.Bytes()
+}
+
+// SignMessage signs the message with the private key of the address
+func (w *Wallet) SignMessage(passphrase, addrStr, msg string) (*SignedMessage, error) {
+	prv, err := w.store.PrivateKey(passphrase, addrStr)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{
+		Address:   addrStr,
+		Message:   msg,
+		PublicKey: prv.PublicKey().String(),
+		Signature: prv.Sign(messageSignBytes(msg)).String(),
+	}, nil
+}
+
+// Proof returns the compact form of the signature: "public_key:signature"
+func (m *SignedMessage) Proof() string {
+	return m.PublicKey + ":" + m.Signature
+}
+
+// JSON returns the signed message in JSON format
+func (m *SignedMessage) JSON() string {
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+// VerifyMessage verifies the signature of the message for the address.
+// The signature can be either in the compact form ("public_key:signature")
+// or the JSON form of the signed message.
+func VerifyMessage(addrStr, sigStr, msg string) error {
+	addr, err := crypto.AddressFromString(addrStr)
+	if err != nil {
+		return err
+	}
+
+	pubStr, signStr := "", ""
+	sigStr = strings.TrimSpace(sigStr)
+	if strings.HasPrefix(sigStr, "{") {
+		m := SignedMessage{}
+		if err := json.Unmarshal([]byte(sigStr), &m); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		if m.Message != msg {
+			return fmt.Errorf("%w: message doesn't match", ErrInvalidSignature)
+		}
+		pubStr, signStr = m.PublicKey, m.Signature
+	} else {
+		parts := strings.Split(sigStr, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("%w: expected public_key:signature", ErrInvalidSignature)
+		}
+		pubStr, signStr = parts[0], parts[1]
+	}
+
+	pub, err := bls.PublicKeyFromString(pubStr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	sig, err := bls.SignatureFromString(signStr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := pub.VerifyAddress(addr); err != nil {
+		return fmt.Errorf("%w: public key doesn't belong to %s", ErrInvalidSignature, addrStr)
+	}
+	if err := pub.Verify(messageSignBytes(msg), sig); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/tx"
+)
+
+func TestSignMessage(t *testing.T) {
+	setup(t)
+
+	addr := firstAddress(t).String()
+	msg := "I own this address"
+	signed, err := tWallet.SignMessage(tPassphrase, addr, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, signed.Address)
+	assert.Equal(t, msg, signed.Message)
+
+	t.Run("Compact form", func(t *testing.T) {
+		assert.NoError(t, VerifyMessage(addr, signed.Proof(), msg))
+	})
+
+	t.Run("JSON form", func(t *testing.T) {
+		assert.NoError(t, VerifyMessage(addr, signed.JSON(), msg))
+	})
+
+	t.Run("Other message", func(t *testing.T) {
+		assert.ErrorIs(t, VerifyMessage(addr, signed.Proof(), msg+"!"), ErrInvalidSignature)
+		assert.ErrorIs(t, VerifyMessage(addr, signed.JSON(), msg+"!"), ErrInvalidSignature)
+	})
+
+	t.Run("Other address", func(t *testing.T) {
+		pub, _ := bls.GenerateTestKeyPair()
+		err := VerifyMessage(pub.Address().String(), signed.Proof(), msg)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		assert.ErrorIs(t, VerifyMessage(addr, "invalid", msg), ErrInvalidSignature)
+		assert.ErrorIs(t, VerifyMessage(addr, signed.PublicKey+":00", msg), ErrInvalidSignature)
+	})
+
+	t.Run("Unknown address", func(t *testing.T) {
+		pub, _ := bls.GenerateTestKeyPair()
+		_, err := tWallet.SignMessage(tPassphrase, pub.Address().String(), msg)
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+	})
+}
+
+func TestMessageIsNotTransaction(t *testing.T) {
+	// A signed message can't be decoded as a transaction
+	trx := new(tx.Tx)
+	data := messageSignBytes("any message")
+	assert.Error(t, trx.DecodeWithNoSignatory(bytes.NewReader(data)))
+}
@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	zarb "github.com/zarbchain/zarb-go/www/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockServer is an in-memory zarb gRPC server for testing
+type mockServer struct {
+	zarb.UnimplementedZarbServer
+
+	height      int32
+	lastHash    hash.Hash
+	genesisHash hash.Hash
+	blockHashes map[int32]hash.Hash
+	accounts    map[crypto.Address]*zarb.AccountInfo
+	validators  map[crypto.Address]*zarb.ValidatorInfo
+	txs         map[tx.ID]*tx.Tx
+	sent        []*tx.Tx
+	sendErr     error
+}
+
+// setupMockServer runs a mock server and connects the test wallet to it
+func setupMockServer(t *testing.T) *mockServer {
+	s := &mockServer{
+		height:      1000,
+		lastHash:    hash.GenerateTestHash(),
+		genesisHash: hash.GenerateTestHash(),
+		blockHashes: make(map[int32]hash.Hash),
+		accounts:    make(map[crypto.Address]*zarb.AccountInfo),
+		validators:  make(map[crypto.Address]*zarb.ValidatorInfo),
+		txs:         make(map[tx.ID]*tx.Tx),
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	zarb.RegisterZarbServer(server, s)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	client, err := MewGRPCClient(listener.Addr().String())
+	assert.NoError(t, err)
+	tWallet.client = client
+
+	return s
+}
+
+func (s *mockServer) addAccount(addr crypto.Address, seq int32, balance int64) {
+	s.accounts[addr] = &zarb.AccountInfo{Address: addr.Bytes(), Sequence: seq, Balance: balance}
+}
+
+func (s *mockServer) addValidator(addr crypto.Address, seq int32, stake int64, unbondingHeight int32) {
+	s.validators[addr] = &zarb.ValidatorInfo{Address: addr.Bytes(), Sequence: seq, Stake: stake, UnbondingHeight: unbondingHeight}
+}
+
+func (s *mockServer) GetBlockchainInfo(_ context.Context, _ *zarb.BlockchainInfoRequest) (*zarb.BlockchainInfoResponse, error) {
+	return &zarb.BlockchainInfoResponse{
+		LastBlockHeight: s.height,
+		LastBlockHash:   s.lastHash.Bytes(),
+	}, nil
+}
+
+func (s *mockServer) GetBlockHash(_ context.Context, req *zarb.BlockHashRequest) (*zarb.BlockHashResponse, error) {
+	if req.Height == 1 {
+		return &zarb.BlockHashResponse{Hash: s.genesisHash.Bytes()}, nil
+	}
+	if req.Height > s.height {
+		return nil, status.Errorf(codes.InvalidArgument, "block not found")
+	}
+	h, ok := s.blockHashes[req.Height]
+	if !ok {
+		h = hash.GenerateTestHash()
+	}
+	return &zarb.BlockHashResponse{Hash: h.Bytes()}, nil
+}
+
+func (s *mockServer) GetAccount(_ context.Context, req *zarb.AccountRequest) (*zarb.AccountResponse, error) {
+	addr, _ := crypto.AddressFromBytes(req.Address)
+	acc, ok := s.accounts[addr]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "account not found")
+	}
+	return &zarb.AccountResponse{Account: acc}, nil
+}
+
+func (s *mockServer) GetValidator(_ context.Context, req *zarb.ValidatorRequest) (*zarb.ValidatorResponse, error) {
+	addr, _ := crypto.AddressFromBytes(req.Address)
+	val, ok := s.validators[addr]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "validator not found")
+	}
+	return &zarb.ValidatorResponse{Validator: val}, nil
+}
+
+func (s *mockServer) GetTransaction(_ context.Context, req *zarb.TransactionRequest) (*zarb.TransactionResponse, error) {
+	id, _ := hash.FromString(req.Id)
+	trx, ok := s.txs[id]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction not found")
+	}
+	return &zarb.TransactionResponse{Tranaction: txToInfo(trx)}, nil
+}
+
+func (s *mockServer) SendRawTransaction(_ context.Context, req *zarb.SendRawTransactionRequest) (*zarb.SendRawTransactionResponse, error) {
+	if s.sendErr != nil {
+		return nil, s.sendErr
+	}
+	data, _ := hex.DecodeString(req.Data)
+	trx, err := tx.FromBytes(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if err := trx.SanityCheck(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	s.sent = append(s.sent, trx)
+	return &zarb.SendRawTransactionResponse{Id: trx.ID().String()}, nil
+}
@@ -0,0 +1,331 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	herumi "github.com/herumi/bls-go-binary/bls"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// A multisig account is controlled by the aggregated public key of its members.
+// The aggregated signature of all the members over a transaction is valid for
+// the aggregated public key, so the node verifies it as a normal signature.
+// The protocol has no multisig script, therefore only n-of-n accounts are
+// supported. An m-of-n account needs threshold keys, which can't be made from
+// the members' independent keys.
+//
+// To prevent rogue-key attacks, each member proves the possession of its
+// private key by signing its public key.
+
+// multisigPopPrefix separates the proof of possession from the other signed messages
+const multisigPopPrefix = "ZARB-MULTISIG-POP:"
+
+// MultisigMember is the public information of a member of a multisig account
+type MultisigMember struct {
+	PublicKey string `json:"public_key"`
+	// Proof is the signature of the member over its public key
+	Proof string `json:"proof"`
+}
+
+// MultisigAccount is an account controlled by several members
+type MultisigAccount struct {
+	Address   string           `json:"address"`
+	Label     string           `json:"label"`
+	Threshold int              `json:"threshold"`
+	Members   []MultisigMember `json:"members"`
+}
+
+// PartialSignature is the signature of a member over a transaction
+type PartialSignature struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// PartialTx is a transaction of a multisig account that is signed by some of its members.
+// It is stored in a file that the members exchange.
+type PartialTx struct {
+	Account    string             `json:"account"`
+	Tx         string             `json:"tx"` // Unsigned transaction in hex
+	Signatures []PartialSignature `json:"signatures"`
+}
+
+func multisigPopMsg(pub *bls.PublicKey) []byte {
+	return append([]byte(multisigPopPrefix), pub.Bytes()...)
+}
+
+// MultisigMember returns the member information of the address,
+// to be shared with the other members.
+func (w *Wallet) MultisigMember(passphrase, addrStr string) (*MultisigMember, error) {
+	prv, err := w.store.PrivateKey(passphrase, addrStr)
+	if err != nil {
+		return nil, err
+	}
+	pub := prv.PublicKey().(*bls.PublicKey)
+	return &MultisigMember{
+		PublicKey: pub.String(),
+		Proof:     prv.Sign(multisigPopMsg(pub)).String(),
+	}, nil
+}
+
+func (m MultisigMember) verify() (*bls.PublicKey, error) {
+	pub, err := bls.PublicKeyFromString(m.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMember, err)
+	}
+	sig, err := bls.SignatureFromString(m.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMember, err)
+	}
+	if err := pub.Verify(multisigPopMsg(pub), sig); err != nil {
+		return nil, fmt.Errorf("%w: invalid proof for %s", ErrInvalidMember, m.PublicKey)
+	}
+	return pub, nil
+}
+
+// aggregatePublicKeys returns the sum of the public keys
+func aggregatePublicKeys(pubs []*bls.PublicKey) (*bls.PublicKey, error) {
+	var agg herumi.PublicKey
+	for i, pub := range pubs {
+		var p herumi.PublicKey
+		if err := p.Deserialize(pub.Bytes()); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			agg = p
+		} else {
+			agg.Add(&p)
+		}
+	}
+	return bls.PublicKeyFromBytes(agg.Serialize())
+}
+
+// publicKey returns the aggregated public key of the members
+func (a *MultisigAccount) publicKey() (*bls.PublicKey, error) {
+	pubs := make([]*bls.PublicKey, len(a.Members))
+	for i, m := range a.Members {
+		pub, err := m.verify()
+		if err != nil {
+			return nil, err
+		}
+		pubs[i] = pub
+	}
+	return aggregatePublicKeys(pubs)
+}
+
+// member returns the index of the member with the given public key
+func (a *MultisigAccount) member(pubStr string) int {
+	for i, m := range a.Members {
+		if m.PublicKey == pubStr {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewMultisigAccount creates a multisig account and adds it to the wallet
+func (w *Wallet) NewMultisigAccount(label string, threshold int, members []MultisigMember) (string, error) {
+	if len(members) < 2 {
+		return "", fmt.Errorf("%w: at least two members are required", ErrInvalidMember)
+	}
+	if threshold != len(members) {
+		return "", fmt.Errorf("%w, threshold: %v, members: %v", ErrThresholdNotSupported, threshold, len(members))
+	}
+	a := MultisigAccount{
+		Label:     label,
+		Threshold: threshold,
+		Members:   members,
+	}
+	for i, m := range members {
+		if a.member(m.PublicKey) != i {
+			return "", fmt.Errorf("%w: duplicated member %s", ErrInvalidMember, m.PublicKey)
+		}
+	}
+	pub, err := a.publicKey()
+	if err != nil {
+		return "", err
+	}
+	a.Address = pub.Address().String()
+	if _, ok := w.MultisigAccount(a.Address); ok {
+		return "", ErrAddressExists
+	}
+
+	w.store.Multisig = append(w.store.Multisig, a)
+	if err := w.saveToFile(); err != nil {
+		return "", err
+	}
+	return a.Address, nil
+}
+
+// MultisigAccounts returns the multisig accounts of the wallet
+func (w *Wallet) MultisigAccounts() []MultisigAccount {
+	return w.store.Multisig
+}
+
+// MultisigAccount returns the multisig account with the given address
+func (w *Wallet) MultisigAccount(addrStr string) (MultisigAccount, bool) {
+	for _, a := range w.store.Multisig {
+		if a.Address == addrStr {
+			return a, true
+		}
+	}
+	return MultisigAccount{}, false
+}
+
+// NewPartialTx creates a partial transaction for a multisig account, without any signature
+func (w *Wallet) NewPartialTx(trx *tx.Tx) (*PartialTx, error) {
+	signer := trx.Payload().Signer().String()
+	if _, ok := w.MultisigAccount(signer); !ok {
+		return nil, fmt.Errorf("%w: %s is not a multisig account", ErrAddressNotFound, signer)
+	}
+	return &PartialTx{
+		Account:    signer,
+		Tx:         hex.EncodeToString(trx.SignBytes()),
+		Signatures: []PartialSignature{},
+	}, nil
+}
+
+// ReadPartialTx reads a partial transaction from the file
+func ReadPartialTx(path string) (*PartialTx, error) {
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(PartialTx)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Save writes the partial transaction into the file
+func (p *PartialTx) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(path, data)
+}
+
+// Transaction decodes the unsigned transaction
+func (p *PartialTx) Transaction() (*tx.Tx, error) {
+	trx, err := DecodeUnsignedTx([]byte(p.Tx))
+	if err != nil {
+		return nil, err
+	}
+	if trx.Payload().Signer().String() != p.Account {
+		return nil, fmt.Errorf("transaction signer doesn't match the account: %s", p.Account)
+	}
+	return trx, nil
+}
+
+// SignPartialTx signs the partial transaction with the member key of this wallet.
+// The signature of the member replaces its previous signature.
+// It refuses to sign if the node is on another chain than the wallet network.
+func (w *Wallet) SignPartialTx(passphrase string, p *PartialTx, memberAddr string) error {
+	if err := w.checkChain(); err != nil {
+		return err
+	}
+	a, ok := w.MultisigAccount(p.Account)
+	if !ok {
+		return fmt.Errorf("%w: %s is not a multisig account", ErrAddressNotFound, p.Account)
+	}
+	trx, err := p.Transaction()
+	if err != nil {
+		return err
+	}
+	prv, err := w.store.PrivateKey(passphrase, memberAddr)
+	if err != nil {
+		return err
+	}
+	pubStr := prv.PublicKey().String()
+	if a.member(pubStr) == -1 {
+		return fmt.Errorf("%w: %s is not a member of %s", ErrInvalidMember, memberAddr, p.Account)
+	}
+
+	sig := PartialSignature{
+		PublicKey: pubStr,
+		Signature: prv.Sign(trx.SignBytes()).String(),
+	}
+	for i, s := range p.Signatures {
+		if s.PublicKey == pubStr {
+			p.Signatures[i] = sig
+			return nil
+		}
+	}
+	p.Signatures = append(p.Signatures, sig)
+	return nil
+}
+
+// MissingSigners returns the public keys of the members that have not signed the transaction yet
+func (w *Wallet) MissingSigners(p *PartialTx) ([]string, error) {
+	a, ok := w.MultisigAccount(p.Account)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a multisig account", ErrAddressNotFound, p.Account)
+	}
+	signed := make(map[string]bool)
+	for _, s := range p.Signatures {
+		signed[s.PublicKey] = true
+	}
+	missing := []string{}
+	for _, m := range a.Members {
+		if !signed[m.PublicKey] {
+			missing = append(missing, m.PublicKey)
+		}
+	}
+	return missing, nil
+}
+
+// CombinePartialTx verifies the signatures of the members and aggregates them.
+// It returns the signed transaction that can be broadcasted.
+func (w *Wallet) CombinePartialTx(p *PartialTx) (*tx.Tx, error) {
+	a, ok := w.MultisigAccount(p.Account)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a multisig account", ErrAddressNotFound, p.Account)
+	}
+	trx, err := p.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	missing, err := w.MissingSigners(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w, %v of %v members have signed", ErrMissingSignatures,
+			len(a.Members)-len(missing), len(a.Members))
+	}
+
+	sigs := []*bls.Signature{}
+	for _, s := range p.Signatures {
+		if a.member(s.PublicKey) == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMember, s.PublicKey)
+		}
+		pub, err := bls.PublicKeyFromString(s.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := bls.SignatureFromString(s.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if err := pub.Verify(trx.SignBytes(), sig); err != nil {
+			return nil, fmt.Errorf("invalid signature of %s: %w", s.PublicKey, err)
+		}
+		sigs = append(sigs, sig)
+	}
+
+	pub, err := a.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	trx.SetPublicKey(pub)
+	trx.SetSignature(bls.Aggregate(sigs))
+	if err := trx.SanityCheck(); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
@@ -0,0 +1,133 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+func multisigMembers(t *testing.T, n int) ([]string, []MultisigMember) {
+	addrs := []string{}
+	members := []MultisigMember{}
+	for i := 0; i < n; i++ {
+		addr, err := tWallet.NewAddress(tPassphrase, "member")
+		assert.NoError(t, err)
+		m, err := tWallet.MultisigMember(tPassphrase, addr)
+		assert.NoError(t, err)
+		addrs = append(addrs, addr)
+		members = append(members, *m)
+	}
+	return addrs, members
+}
+
+func TestNewMultisigAccount(t *testing.T) {
+	setup(t)
+	_, members := multisigMembers(t, 3)
+
+	t.Run("m-of-n is not supported", func(t *testing.T) {
+		_, err := tWallet.NewMultisigAccount("treasury", 2, members)
+		assert.ErrorIs(t, err, ErrThresholdNotSupported)
+	})
+
+	t.Run("Single member", func(t *testing.T) {
+		_, err := tWallet.NewMultisigAccount("treasury", 1, members[:1])
+		assert.ErrorIs(t, err, ErrInvalidMember)
+	})
+
+	t.Run("Duplicated member", func(t *testing.T) {
+		_, err := tWallet.NewMultisigAccount("treasury", 2, []MultisigMember{members[0], members[0]})
+		assert.ErrorIs(t, err, ErrInvalidMember)
+	})
+
+	t.Run("Invalid proof of possession", func(t *testing.T) {
+		invalid := []MultisigMember{members[0], {PublicKey: members[1].PublicKey, Proof: members[0].Proof}}
+		_, err := tWallet.NewMultisigAccount("treasury", 2, invalid)
+		assert.ErrorIs(t, err, ErrInvalidMember)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		addr, err := tWallet.NewMultisigAccount("treasury", 3, members)
+		assert.NoError(t, err)
+
+		_, err = tWallet.NewMultisigAccount("treasury", 3, members)
+		assert.ErrorIs(t, err, ErrAddressExists)
+
+		reopenWallet(t)
+		a, ok := tWallet.MultisigAccount(addr)
+		assert.True(t, ok)
+		assert.Equal(t, "treasury", a.Label)
+		assert.Equal(t, 3, a.Threshold)
+		assert.Equal(t, members, a.Members)
+	})
+}
+
+func TestMultisigSigning(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	memberAddrs, members := multisigMembers(t, 2)
+	accStr, err := tWallet.NewMultisigAccount("treasury", 2, members)
+	assert.NoError(t, err)
+	acc, _ := crypto.AddressFromString(accStr)
+	server.addAccount(acc, 0, 10*UnitsPerCoin)
+
+	trx, err := tWallet.MakeSendTx("", "", accStr, crypto.GenerateTestAddress().String(), "1", "", "treasury", false)
+	assert.NoError(t, err)
+
+	partial, err := tWallet.NewPartialTx(trx)
+	assert.NoError(t, err)
+
+	t.Run("Not a multisig account", func(t *testing.T) {
+		trx, err := tWallet.MakeSendTx("", "1", memberAddrs[0], accStr, "1", "", "", true)
+		assert.NoError(t, err)
+		_, err = tWallet.NewPartialTx(trx)
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+	})
+
+	t.Run("Missing signatures", func(t *testing.T) {
+		assert.NoError(t, tWallet.SignPartialTx(tPassphrase, partial, memberAddrs[0]))
+		// Signing twice doesn't add a new signature
+		assert.NoError(t, tWallet.SignPartialTx(tPassphrase, partial, memberAddrs[0]))
+		assert.Len(t, partial.Signatures, 1)
+
+		missing, err := tWallet.MissingSigners(partial)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{members[1].PublicKey}, missing)
+
+		_, err = tWallet.CombinePartialTx(partial)
+		assert.ErrorIs(t, err, ErrMissingSignatures)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		addr, err := tWallet.NewAddress(tPassphrase, "not-member")
+		assert.NoError(t, err)
+		err = tWallet.SignPartialTx(tPassphrase, partial, addr)
+		assert.ErrorIs(t, err, ErrInvalidMember)
+	})
+
+	t.Run("Exchange the file and combine", func(t *testing.T) {
+		path := util.TempFilePath()
+		assert.NoError(t, partial.Save(path))
+		loaded, err := ReadPartialTx(path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, tWallet.SignPartialTx(tPassphrase, loaded, memberAddrs[1]))
+		signed, err := tWallet.CombinePartialTx(loaded)
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), signed.ID())
+		assert.Equal(t, acc, signed.PublicKey().Address())
+
+		_, err = tWallet.Broadcast(signed)
+		assert.NoError(t, err)
+		assert.Len(t, server.sent, 1)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		assert.NoError(t, tWallet.SignPartialTx(tPassphrase, partial, memberAddrs[1]))
+		partial.Signatures[0].Signature = partial.Signatures[1].Signature
+		_, err := tWallet.CombinePartialTx(partial)
+		assert.Error(t, err)
+	})
+}
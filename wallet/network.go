@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/genesis"
+	"github.com/zarbchain/zarb-go/param"
+)
+
+// Network is the blockchain network of the wallet
+type Network int
+
+const (
+	Mainnet  Network = 0
+	Testnet  Network = 1
+	Localnet Network = 2
+)
+
+// Networks are the names of the supported networks
+var Networks = []string{Mainnet.String(), Testnet.String(), Localnet.String()}
+
+// ParseNetwork parses the name of a network
+func ParseNetwork(name string) (Network, error) {
+	switch strings.ToLower(name) {
+	case "mainnet":
+		return Mainnet, nil
+	case "testnet":
+		return Testnet, nil
+	case "localnet":
+		return Localnet, nil
+	default:
+		return 0, fmt.Errorf("%w: %s, expected %s", ErrInvalidNetwork, name, strings.Join(Networks, ", "))
+	}
+}
+
+func (n Network) String() string {
+	switch n {
+	case Mainnet:
+		return "mainnet"
+	case Testnet:
+		return "testnet"
+	case Localnet:
+		return "localnet"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(n))
+	}
+}
+
+func (n Network) isValid() bool {
+	return n == Mainnet || n == Testnet || n == Localnet
+}
+
+// testnetParams are the consensus parameters of the testnet genesis
+var testnetParams = genesis.Testnet().Params()
+
+// params returns the consensus parameters of the network.
+// The node doesn't expose them through gRPC, so they are taken from the genesis
+// of the network. The local networks are made by `zarbd init` with the default
+// parameters. The mainnet is not launched yet, it uses the default parameters too.
+func (n Network) params() param.Params {
+	if n == Testnet {
+		return testnetParams
+	}
+	return param.DefaultParams()
+}
+
+// keyInfo separates the keys of the networks, so the same seed phrase
+// derives different keys on each network.
+// Mainnet keeps the empty key info of the first wallets, and the wallets
+// before the store version 2 use it on all the networks.
+func (n Network) keyInfo(storeVersion int) []byte {
+	if n == Mainnet || storeVersion < 2 {
+		return []byte{}
+	}
+	return []byte("ZARB-" + strings.ToUpper(n.String()))
+}
+
+// The address format, including the "zc" prefix, is defined by the crypto
+// package of zarb-go and the nodes of all the networks parse the addresses
+// with it. Therefore the addresses are encoded the same on all networks, and
+// the wallet protects against using the wrong network by checking the chain
+// of the node (see checkChain) and by the network-separated keys.
+
+// checkChain makes sure the node is on the chain of the wallet network.
+// The node doesn't expose a chain ID, so the hash of the first block identifies
+// the chain. It is compared with the genesis hash of the network in servers.json.
+// The hash of the first block can't be derived from the genesis document, so
+// for the networks without a pinned hash, like the local networks that are
+// reset often, the first chain that the wallet sees is recorded and checked
+// afterwards.
+func (w *Wallet) checkChain() error {
+	if w.chainChecked {
+		return nil
+	}
+	net := Network(w.store.Network)
+	expected := networkInfos[net.String()].GenesisHash
+	h, err := w.client.GetBlockHash(1)
+	if err != nil {
+		return err
+	}
+	chainID := h.String()
+	if expected == "" {
+		if w.store.ChainID == "" {
+			w.store.ChainID = chainID
+			if err := w.saveToFile(); err != nil {
+				return err
+			}
+		}
+		expected = w.store.ChainID
+	}
+	if chainID != expected {
+		return fmt.Errorf("%w: the node is not on the %s chain of the wallet", ErrChainMismatch, net)
+	}
+	w.chainChecked = true
+	return nil
+}
+
+// Network returns the network of the wallet
+func (w *Wallet) Network() Network {
+	return Network(w.store.Network)
+}
+
+// params returns the consensus parameters of the wallet network
+func (w *Wallet) params() param.Params {
+	return w.Network().params()
+}
@@ -0,0 +1,126 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseNetwork(t *testing.T) {
+	for _, net := range []Network{Mainnet, Testnet, Localnet} {
+		parsed, err := ParseNetwork(net.String())
+		assert.NoError(t, err)
+		assert.Equal(t, net, parsed)
+	}
+	_, err := ParseNetwork("devnet")
+	assert.ErrorIs(t, err, ErrInvalidNetwork)
+}
+
+func TestNetworkKeys(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	addrs := map[Network]string{}
+	for _, net := range []Network{Mainnet, Testnet, Localnet} {
+		w, err := RecoverWallet(util.TempFilePath(), mnemonic, int(net))
+		assert.NoError(t, err)
+		assert.Equal(t, net, w.Network())
+
+		addr, err := w.NewAddress("", "")
+		assert.NoError(t, err)
+		addrs[net] = addr
+	}
+	assert.NotEqual(t, addrs[Mainnet], addrs[Testnet])
+	assert.NotEqual(t, addrs[Mainnet], addrs[Localnet])
+	assert.NotEqual(t, addrs[Testnet], addrs[Localnet])
+
+	t.Run("Legacy keys", func(t *testing.T) {
+		for _, net := range []Network{Mainnet, Testnet, Localnet} {
+			w, err := RecoverLegacyWallet(util.TempFilePath(), mnemonic, int(net))
+			assert.NoError(t, err)
+			assert.Equal(t, legacyStoreVersion, w.store.Version)
+
+			// The wallets before the store version 2 use the mainnet keys
+			addr, err := w.NewAddress("", "")
+			assert.NoError(t, err)
+			assert.Equal(t, addrs[Mainnet], addr)
+
+			reopened, err := OpenWallet(w.Path())
+			assert.NoError(t, err)
+			prv, err := reopened.PrivateKey("", addr)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, prv)
+		}
+	})
+
+	// The keys of the old wallets are not changed
+	assert.Empty(t, Mainnet.keyInfo(storeVersion))
+	assert.Empty(t, Testnet.keyInfo(1))
+}
+
+func TestCheckChain(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+
+	// The servers.json is not patched, the networks have no pinned hash
+	trx, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", "", false)
+	assert.NoError(t, err)
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+	assert.NoError(t, err)
+	assert.Equal(t, server.genesisHash.String(), tWallet.store.ChainID, "the first chain should be recorded")
+
+	t.Run("Recorded chain is persisted", func(t *testing.T) {
+		client := tWallet.client
+		reopenWallet(t)
+		tWallet.client = client
+		assert.NoError(t, tWallet.checkChain())
+	})
+
+	t.Run("Node on another chain", func(t *testing.T) {
+		tWallet.chainChecked = false
+		server.genesisHash = hash.GenerateTestHash()
+		trx, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", "", false)
+		assert.NoError(t, err)
+		_, err = tWallet.SignTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrChainMismatch)
+		assert.Nil(t, trx.Signature(), "transaction should not be signed")
+	})
+
+	t.Run("Pinned genesis hash", func(t *testing.T) {
+		net := tWallet.Network().String()
+		orig := networkInfos[net]
+		t.Cleanup(func() { networkInfos[net] = orig })
+		info := orig
+		info.GenesisHash = server.genesisHash.String()
+		networkInfos[net] = info
+
+		// The pinned hash takes precedence over the recorded one
+		tWallet.chainChecked = false
+		assert.NoError(t, tWallet.checkChain())
+
+		tWallet.chainChecked = false
+		server.genesisHash = hash.GenerateTestHash()
+		assert.ErrorIs(t, tWallet.checkChain(), ErrChainMismatch)
+	})
+}
+
+func TestNetworkServers(t *testing.T) {
+	// The mainnet is not launched yet
+	assert.Empty(t, networkInfos[Mainnet.String()].Servers)
+	assert.NotEmpty(t, networkInfos[Testnet.String()].Servers)
+	assert.NotEmpty(t, networkInfos[Localnet.String()].Servers)
+
+	w, err := CreateWallet(util.TempFilePath(), "", int(Mainnet))
+	assert.NoError(t, err)
+	_, err = w.client.GetStamp()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), "mainnet")
+}
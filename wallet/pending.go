@@ -0,0 +1,260 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+)
+
+// PendingTx is a broadcasted transaction that is not committed yet.
+// The wallet keeps track of the pending transactions to allocate
+// the next sequences locally, without waiting for the node.
+type PendingTx struct {
+	ID       string       `json:"id"`
+	Type     payload.Type `json:"type"`
+	Signer   string       `json:"signer"`
+	Sequence int32        `json:"sequence"`
+	// Value is the amount spent by the signer, including the fee
+	Value int64 `json:"value"`
+	// Height is the height of the block that the stamp of the transaction refers to,
+	// the transaction expires after the transaction-to-live interval (TTL)
+	// of the wallet network
+	Height    int32     `json:"height"`
+	TTL       int32     `json:"ttl"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+	// Expired transactions can't be committed anymore, they are kept
+	// with their data to be resubmitted, until their sequence is committed
+	Expired bool `json:"expired,omitempty"`
+}
+
+// signedByValidator returns true if the transaction is signed by a validator.
+// Accounts and validators have their own sequences.
+func signedByValidator(t payload.Type) bool {
+	return t == payload.PayloadTypeUnbond ||
+		t == payload.PayloadTypeWithdraw ||
+		t == payload.PayloadTypeSortition
+}
+
+// ExpiryHeight returns the height that the transaction expires after it
+func (p PendingTx) ExpiryHeight() int32 {
+	return p.Height + p.TTL
+}
+
+func (p PendingTx) isValidator() bool {
+	return signedByValidator(p.Type)
+}
+
+// PendingTxs reconciles the pending transactions with the blockchain state
+// and returns the ones which are not committed yet, including the expired ones.
+func (w *Wallet) PendingTxs() ([]PendingTx, error) {
+	if len(w.store.Pending) == 0 {
+		return nil, nil
+	}
+	info, err := w.client.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	type signerKey struct {
+		signer    string
+		validator bool
+	}
+	checked := make(map[signerKey]bool)
+	changed := false
+	for _, p := range w.store.Pending {
+		key := signerKey{p.Signer, p.isValidator()}
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		signer, err := crypto.AddressFromString(p.Signer)
+		if err != nil {
+			return nil, err
+		}
+		nodeNext, err := w.nodeSequence(signer, key.validator)
+		if err != nil {
+			return nil, err
+		}
+		if w.reconcilePending(signer, key.validator, nodeNext, info.LastBlockHeight) {
+			changed = true
+		}
+	}
+	if changed {
+		if err := w.saveToFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	pending := make([]PendingTx, len(w.store.Pending))
+	copy(pending, w.store.Pending)
+	return pending, nil
+}
+
+// nodeSequence returns the next sequence of the signer, based on the committed transactions
+func (w *Wallet) nodeSequence(signer crypto.Address, validator bool) (int32, error) {
+	if validator {
+		return w.client.GetValidatorSequence(signer)
+	}
+	return w.client.GetAccountSequence(signer)
+}
+
+// nextSequence returns the next sequence of the signer,
+// considering both the blockchain state and the pending transactions.
+func (w *Wallet) nextSequence(signer crypto.Address, validator bool) (int32, error) {
+	nodeNext, err := w.nodeSequence(signer, validator)
+	if err != nil {
+		return -1, err
+	}
+	if len(w.pendingOf(signer, validator)) == 0 {
+		return nodeNext, nil
+	}
+	info, err := w.client.GetBlockchainInfo()
+	if err != nil {
+		return -1, err
+	}
+	w.reconcilePending(signer, validator, nodeNext, info.LastBlockHeight)
+
+	return w.pendingNext(signer, validator, nodeNext), nil
+}
+
+// pendingOf returns the pending transactions of the signer, sorted by sequence
+func (w *Wallet) pendingOf(signer crypto.Address, validator bool) []PendingTx {
+	pending := []PendingTx{}
+	for _, p := range w.store.Pending {
+		if p.Signer == signer.String() && p.isValidator() == validator {
+			pending = append(pending, p)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence < pending[j].Sequence })
+	return pending
+}
+
+// pendingNext returns the sequence after the last pending transaction of the signer.
+// The sequences of the expired transactions can be used again.
+func (w *Wallet) pendingNext(signer crypto.Address, validator bool, nodeNext int32) int32 {
+	next := nodeNext
+	for _, p := range w.pendingOf(signer, validator) {
+		if !p.Expired && p.Sequence >= next {
+			next = p.Sequence + 1
+		}
+	}
+	return next
+}
+
+// pendingValue returns the amount spent by the pending transactions of the signer,
+// which have a sequence lower than the given sequence.
+func (w *Wallet) pendingValue(signer crypto.Address, validator bool, seq int32) int64 {
+	value := int64(0)
+	for _, p := range w.pendingOf(signer, validator) {
+		if !p.Expired && p.Sequence < seq {
+			value += p.Value
+		}
+	}
+	return value
+}
+
+// reconcilePending removes the pending transactions of the signer which are
+// committed (or replaced) and marks the expired ones.
+// If a transaction is expired, the next transactions of the signer can't be
+// committed anymore, so they are marked as expired as well.
+// It returns true if any transaction is removed or marked.
+func (w *Wallet) reconcilePending(signer crypto.Address, validator bool, nodeNext, height int32) bool {
+	expiredSeq := int32(-1)
+	for _, p := range w.pendingOf(signer, validator) {
+		if p.Sequence >= nodeNext && height > p.ExpiryHeight() {
+			expiredSeq = p.Sequence
+			break
+		}
+	}
+
+	changed := false
+	kept := make([]PendingTx, 0, len(w.store.Pending))
+	for _, p := range w.store.Pending {
+		if p.Signer == signer.String() && p.isValidator() == validator {
+			if p.Sequence < nodeNext {
+				changed = true
+				continue
+			}
+			if expiredSeq != -1 && p.Sequence >= expiredSeq && !p.Expired {
+				p.Expired = true
+				changed = true
+			}
+		}
+		kept = append(kept, p)
+	}
+	w.store.Pending = kept
+	return changed
+}
+
+// addPending records a broadcasted transaction as pending
+// A pending transaction with the same sequence is replaced.
+func (w *Wallet) addPending(trx *tx.Tx, data []byte, height int32) {
+	signer := trx.Payload().Signer().String()
+	validator := signedByValidator(trx.Payload().Type())
+	kept := make([]PendingTx, 0, len(w.store.Pending)+1)
+	for _, p := range w.store.Pending {
+		if p.Signer == signer && p.isValidator() == validator && p.Sequence == trx.Sequence() {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	w.store.Pending = append(kept, PendingTx{
+		ID:        trx.ID().String(),
+		Type:      trx.Payload().Type(),
+		Signer:    signer,
+		Sequence:  trx.Sequence(),
+		Value:     trx.Payload().Value() + trx.Fee(),
+		Height:    height,
+		TTL:       w.params().TransactionToLiveInterval,
+		Data:      hex.EncodeToString(data),
+		CreatedAt: time.Now().Round(time.Second).UTC(),
+	})
+}
+
+// maxStampLookup is the number of the recent blocks that are searched
+// to find the block of a transaction stamp
+const maxStampLookup = 100
+
+// stampHeight returns the height of the block that the stamp refers to.
+// If the block is not found among the recent blocks, it returns the oldest
+// height that a valid stamp can have, so the wallet never considers
+// a transaction valid for longer than the node does.
+func (w *Wallet) stampHeight(stamp hash.Stamp, lastHeight int32, lastHash hash.Hash) int32 {
+	if lastHash.Stamp().EqualsTo(stamp) {
+		return lastHeight
+	}
+	oldest := lastHeight - w.params().TransactionToLiveInterval
+	for h := lastHeight - 1; h > 0 && h > oldest && lastHeight-h <= maxStampLookup; h-- {
+		blockHash, err := w.client.GetBlockHash(h)
+		if err != nil {
+			break
+		}
+		if blockHash.Stamp().EqualsTo(stamp) {
+			return h
+		}
+	}
+	if oldest < 0 {
+		return 0
+	}
+	return oldest
+}
+
+// checkSequence checks the sequence of the transaction.
+// The sequence can be any sequence between the next committed sequence and
+// the sequence after the pending transactions of the signer.
+func (w *Wallet) checkSequence(trx *tx.Tx, nodeNext int32) error {
+	signer := trx.Payload().Signer()
+	next := w.pendingNext(signer, signedByValidator(trx.Payload().Type()), nodeNext)
+	if trx.Sequence() < nodeNext || trx.Sequence() > next {
+		return fmt.Errorf("%w, expected: %v, got: %v", ErrInvalidSequence, next, trx.Sequence())
+	}
+	return nil
+}
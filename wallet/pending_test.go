@@ -0,0 +1,195 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/genesis"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+)
+
+func TestPendingSequence(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+
+	trx1, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(11), trx1.Sequence())
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx1)
+	assert.NoError(t, err)
+
+	// The first transaction is not committed yet
+	trx2, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(12), trx2.Sequence())
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx2)
+	assert.NoError(t, err)
+
+	t.Run("Pending transactions are saved", func(t *testing.T) {
+		client := tWallet.client
+		reopenWallet(t)
+		tWallet.client = client
+
+		pending, err := tWallet.PendingTxs()
+		assert.NoError(t, err)
+		assert.Len(t, pending, 2)
+		assert.Equal(t, trx1.ID().String(), pending[0].ID)
+		assert.Equal(t, sender.String(), pending[0].Signer)
+		assert.Equal(t, int32(11), pending[0].Sequence)
+		assert.Equal(t, server.height+tWallet.params().TransactionToLiveInterval, pending[0].ExpiryHeight())
+	})
+
+	t.Run("Committed transactions are removed", func(t *testing.T) {
+		server.accounts[sender].Sequence = 11
+
+		pending, err := tWallet.PendingTxs()
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, trx2.ID().String(), pending[0].ID)
+
+		seq, err := tWallet.parsAccSeq(sender, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(13), seq)
+	})
+
+	t.Run("Expired transactions are kept", func(t *testing.T) {
+		server.height += tWallet.params().TransactionToLiveInterval + 1
+
+		seq, err := tWallet.parsAccSeq(sender, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(12), seq)
+
+		pending, err := tWallet.PendingTxs()
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+		assert.True(t, pending[0].Expired)
+		assert.NotEmpty(t, pending[0].Data)
+	})
+
+	t.Run("Expired transactions are removed after their sequence is committed", func(t *testing.T) {
+		server.accounts[sender].Sequence = 12
+
+		pending, err := tWallet.PendingTxs()
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
+
+func TestPendingExpiryFromStamp(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+	ttl := tWallet.params().TransactionToLiveInterval
+	server.height = 2 * ttl
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+
+	t.Run("Stamp of an older block", func(t *testing.T) {
+		oldHash := hash.GenerateTestHash()
+		server.blockHashes[server.height-10] = oldHash
+
+		trx, err := tWallet.MakeSendTx(oldHash.Stamp().String(), "", sender.String(), receiver.String(), "1", "", "", false)
+		assert.NoError(t, err)
+		_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+		assert.NoError(t, err)
+
+		p, ok := tWallet.findPending(trx.ID().String())
+		assert.True(t, ok)
+		assert.Equal(t, server.height-10+ttl, p.ExpiryHeight())
+	})
+
+	t.Run("Unknown stamp", func(t *testing.T) {
+		trx, err := tWallet.MakeSendTx(hash.GenerateTestStamp().String(), "", sender.String(), receiver.String(), "1", "", "", false)
+		assert.NoError(t, err)
+		_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+		assert.NoError(t, err)
+
+		p, ok := tWallet.findPending(trx.ID().String())
+		assert.True(t, ok)
+		assert.Equal(t, server.height, p.ExpiryHeight(), "the oldest valid stamp should be assumed")
+	})
+}
+
+func TestPendingNetworkTTL(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+	tWallet.store.Network = int(Testnet)
+	ttl := genesis.Testnet().Params().TransactionToLiveInterval
+	server.height = 2 * ttl
+	lastHash := hash.GenerateTestHash()
+
+	assert.Equal(t, server.height, tWallet.stampHeight(lastHash.Stamp(), server.height, lastHash))
+	assert.Equal(t, server.height-ttl, tWallet.stampHeight(hash.GenerateTestStamp(), server.height, lastHash),
+		"the oldest valid stamp of the testnet should be assumed")
+
+	trx, _ := tx.GenerateTestSendTx()
+	tWallet.addPending(trx, []byte{}, server.height)
+	p, ok := tWallet.findPending(trx.ID().String())
+	assert.True(t, ok)
+	assert.Equal(t, server.height+ttl, p.ExpiryHeight())
+}
+
+func TestPendingBalance(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 1, 3*UnitsPerCoin)
+
+	trx, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "2", "", "", false)
+	assert.NoError(t, err)
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+	assert.NoError(t, err)
+
+	_, err = tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "2", "", "", false)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// Replacing the pending transaction is allowed
+	_, err = tWallet.MakeSendTx("", "2", sender.String(), receiver.String(), "2", "", "", false)
+	assert.NoError(t, err)
+
+	_, err = tWallet.MakeSendTx("", "4", sender.String(), receiver.String(), "0.1", "", "", false)
+	assert.ErrorIs(t, err, ErrInvalidSequence)
+}
+
+func TestReconcilePendingAfterExpiry(t *testing.T) {
+	setup(t)
+
+	signer := crypto.GenerateTestAddress()
+	ttl := tWallet.params().TransactionToLiveInterval
+	tWallet.store.Pending = []PendingTx{
+		{ID: "1", Signer: signer.String(), Sequence: 5, Height: 300, TTL: ttl},
+		{ID: "2", Signer: signer.String(), Sequence: 6, Height: 200, TTL: ttl},
+		{ID: "3", Signer: signer.String(), Sequence: 7, Height: 300, TTL: ttl},
+		{ID: "4", Signer: signer.String(), Sequence: 1, Height: 100, TTL: ttl, Type: payload.PayloadTypeUnbond},
+	}
+
+	// The second one is expired, the third one can't be committed anymore
+	height := 200 + ttl + 1
+	assert.True(t, tWallet.reconcilePending(signer, false, 5, height))
+	assert.Len(t, tWallet.store.Pending, 4)
+	assert.False(t, tWallet.store.Pending[0].Expired)
+	assert.True(t, tWallet.store.Pending[1].Expired)
+	assert.True(t, tWallet.store.Pending[2].Expired)
+	assert.False(t, tWallet.store.Pending[3].Expired)
+
+	// The sequences of the expired transactions can be used again
+	assert.Equal(t, int32(6), tWallet.pendingNext(signer, false, 5))
+
+	assert.False(t, tWallet.reconcilePending(signer, false, 5, height))
+
+	// The sequence of the first expired one is committed by another transaction
+	assert.True(t, tWallet.reconcilePending(signer, false, 7, height))
+	assert.Len(t, tWallet.store.Pending, 2)
+	assert.Equal(t, "3", tWallet.store.Pending[0].ID)
+	assert.Equal(t, "4", tWallet.store.Pending[1].ID)
+}
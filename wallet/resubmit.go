@@ -0,0 +1,101 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+)
+
+// MakeResubmitTx rebuilds a pending transaction with a fresh stamp,
+// keeping its sequence, payload and memo. Expired transactions can be
+// resubmitted too, until their sequence is committed. If the fee is not set,
+// the fee of the original transaction is used.
+// Since the sequence is not changed, at most one of the transactions can be
+// committed, so resubmitting a transaction never spends twice.
+// If the original transaction is already committed, it returns ErrTxConfirmed.
+func (w *Wallet) MakeResubmitTx(idStr, stampStr, feeStr string, force bool) (*tx.Tx, error) {
+	id, err := hash.FromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+	pending, ok := w.findPending(id.String())
+	if !ok {
+		return nil, ErrTxNotPending
+	}
+
+	_, err = w.client.GetTransaction(id)
+	if err == nil {
+		if err := w.removePending(id.String()); err != nil {
+			return nil, err
+		}
+		return nil, ErrTxConfirmed
+	}
+	if !errors.Is(err, ErrTxNotFound) {
+		return nil, err
+	}
+
+	signer, err := crypto.AddressFromString(pending.Signer)
+	if err != nil {
+		return nil, err
+	}
+	nodeNext, err := w.nodeSequence(signer, pending.isValidator())
+	if err != nil {
+		return nil, err
+	}
+	if nodeNext > pending.Sequence {
+		// Another transaction with the same sequence is committed
+		if err := w.removePending(id.String()); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w, sequence %v is already committed", ErrInvalidSequence, pending.Sequence)
+	}
+
+	data, err := hex.DecodeString(pending.Data)
+	if err != nil {
+		return nil, err
+	}
+	orig, err := tx.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp, err := w.parsStamp(stampStr)
+	if err != nil {
+		return nil, err
+	}
+	fee := orig.Fee()
+	if feeStr != "" {
+		amt, err := w.ParseAmount(feeStr)
+		if err != nil {
+			return nil, err
+		}
+		fee = int64(amt)
+	}
+
+	trx := tx.NewTx(stamp, orig.Sequence(), orig.Payload(), fee, orig.Memo())
+	return w.preflightCheck(trx, force)
+}
+
+func (w *Wallet) findPending(id string) (PendingTx, bool) {
+	for _, p := range w.store.Pending {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return PendingTx{}, false
+}
+
+func (w *Wallet) removePending(id string) error {
+	kept := make([]PendingTx, 0, len(w.store.Pending))
+	for _, p := range w.store.Pending {
+		if p.ID != id {
+			kept = append(kept, p)
+		}
+	}
+	w.store.Pending = kept
+	return w.saveToFile()
+}
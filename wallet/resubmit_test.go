@@ -0,0 +1,113 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+)
+
+func broadcastTestSendTx(t *testing.T, sender crypto.Address) *tx.Tx {
+	receiver := crypto.GenerateTestAddress()
+	trx, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", "memo", false)
+	assert.NoError(t, err)
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+	assert.NoError(t, err)
+	return trx
+}
+
+func TestResubmitTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+	orig := broadcastTestSendTx(t, sender)
+
+	t.Run("Unknown transaction", func(t *testing.T) {
+		_, err := tWallet.MakeResubmitTx(hash.GenerateTestHash().String(), "", "", false)
+		assert.ErrorIs(t, err, ErrTxNotPending)
+	})
+
+	t.Run("Rebuild with a new stamp", func(t *testing.T) {
+		server.lastHash = hash.GenerateTestHash()
+
+		trx, err := tWallet.MakeResubmitTx(orig.ID().String(), "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, server.lastHash.Stamp(), trx.Stamp())
+		assert.Equal(t, orig.Sequence(), trx.Sequence())
+		assert.Equal(t, orig.Payload(), trx.Payload())
+		assert.Equal(t, orig.Fee(), trx.Fee())
+		assert.Equal(t, orig.Memo(), trx.Memo())
+
+		_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+		assert.NoError(t, err)
+
+		// The original transaction is replaced
+		pending, err := tWallet.PendingTxs()
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, trx.ID().String(), pending[0].ID)
+	})
+}
+
+func TestResubmitExpiredTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+	orig := broadcastTestSendTx(t, sender)
+
+	server.height += tWallet.params().TransactionToLiveInterval + 1
+	pending, err := tWallet.PendingTxs()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.True(t, pending[0].Expired)
+
+	server.lastHash = hash.GenerateTestHash()
+	trx, err := tWallet.MakeResubmitTx(orig.ID().String(), "", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, orig.Sequence(), trx.Sequence())
+	assert.Equal(t, orig.Payload(), trx.Payload())
+
+	_, err = tWallet.SignAndBroadcast(tPassphrase, trx)
+	assert.NoError(t, err)
+
+	pending, err = tWallet.PendingTxs()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, trx.ID().String(), pending[0].ID)
+	assert.False(t, pending[0].Expired)
+}
+
+func TestResubmitConfirmedTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	server.addAccount(sender, 10, 100*UnitsPerCoin)
+
+	t.Run("Original is committed", func(t *testing.T) {
+		orig := broadcastTestSendTx(t, sender)
+		server.txs[orig.ID()] = orig
+		server.accounts[sender].Sequence = orig.Sequence()
+
+		_, err := tWallet.MakeResubmitTx(orig.ID().String(), "", "", false)
+		assert.ErrorIs(t, err, ErrTxConfirmed)
+		_, ok := tWallet.findPending(orig.ID().String())
+		assert.False(t, ok)
+	})
+
+	t.Run("Sequence is used by another transaction", func(t *testing.T) {
+		orig := broadcastTestSendTx(t, sender)
+		server.accounts[sender].Sequence = orig.Sequence()
+
+		_, err := tWallet.MakeResubmitTx(orig.ID().String(), "", "", false)
+		assert.ErrorIs(t, err, ErrInvalidSequence)
+		_, ok := tWallet.findPending(orig.ID().String())
+		assert.False(t, ok)
+	})
+}
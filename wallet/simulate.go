@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+)
+
+// SimulateTx signs the transaction and checks it against the blockchain state,
+// without broadcasting it. It returns the signed transaction in bytes.
+func (w *Wallet) SimulateTx(passphrase string, trx *tx.Tx) ([]byte, error) {
+	bs, err := w.SignTx(passphrase, trx)
+	if err != nil {
+		return nil, err
+	}
+	if err := trx.SanityCheck(); err != nil {
+		return nil, err
+	}
+	if err := w.checkTx(trx); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// maxMemoLength is the maximum length of the transaction memo, defined by the protocol
+const maxMemoLength = 64
+
+func checkMemo(memo string) error {
+	if len(memo) > maxMemoLength {
+		return fmt.Errorf("%w, maximum is %v characters", ErrMemoTooLong, maxMemoLength)
+	}
+	return nil
+}
+
+// preflightCheck rejects the transactions that are obviously invalid,
+// unless it is forced.
+func (w *Wallet) preflightCheck(trx *tx.Tx, force bool) (*tx.Tx, error) {
+	if !force {
+		if err := w.checkTx(trx); err != nil {
+			return nil, err
+		}
+	}
+	return trx, nil
+}
+
+// checkTx checks the transaction against the blockchain state,
+// the same way the node executes it.
+func (w *Wallet) checkTx(trx *tx.Tx) error {
+	if err := w.checkFee(trx); err != nil {
+		return err
+	}
+
+	switch pld := trx.Payload().(type) {
+	case *payload.SendPayload:
+		return w.checkAccount(trx, pld.Amount)
+
+	case *payload.BondPayload:
+		if err := w.checkAccount(trx, pld.Stake); err != nil {
+			return err
+		}
+		// A validator can be bonded for the first time
+		val, err := w.client.GetValidator(pld.PublicKey.Address())
+		if err == nil && val.UnbondingHeight > 0 {
+			return fmt.Errorf("%w at height %v", ErrValidatorUnbonded, val.UnbondingHeight)
+		}
+
+	case *payload.UnbondPayload:
+		val, err := w.client.GetValidator(pld.Validator)
+		if err != nil {
+			return notFoundError(err, ErrValidatorNotFound)
+		}
+		if err := w.checkSequence(trx, val.Sequence+1); err != nil {
+			return err
+		}
+		if val.UnbondingHeight > 0 {
+			return fmt.Errorf("%w at height %v", ErrValidatorUnbonded, val.UnbondingHeight)
+		}
+
+	case *payload.SortitionPayload:
+		// The proof can't be verified here, since the node doesn't expose the sortition seed
+		val, err := w.client.GetValidator(pld.Address)
+		if err != nil {
+			return notFoundError(err, ErrValidatorNotFound)
+		}
+		if err := w.checkSequence(trx, val.Sequence+1); err != nil {
+			return err
+		}
+		if val.UnbondingHeight > 0 {
+			return fmt.Errorf("%w at height %v", ErrValidatorUnbonded, val.UnbondingHeight)
+		}
+		info, err := w.client.GetBlockchainInfo()
+		if err != nil {
+			return err
+		}
+		bondedHeight := val.LastBondingHeight + w.params().BondInterval
+		if info.LastBlockHeight+1 < bondedHeight {
+			return fmt.Errorf("%w, validator can join the committee at height %v", ErrValidatorNotBonded, bondedHeight)
+		}
+
+	case *payload.WithdrawPayload:
+		val, err := w.client.GetValidator(pld.From)
+		if err != nil {
+			return notFoundError(err, ErrValidatorNotFound)
+		}
+		if err := w.checkSequence(trx, val.Sequence+1); err != nil {
+			return err
+		}
+		stake := val.Stake - w.pendingValue(pld.From, true, trx.Sequence())
+		if stake < pld.Amount+trx.Fee() {
+			return fmt.Errorf("%w, stake: %v, required: %v", ErrInsufficientFunds, stake, pld.Amount+trx.Fee())
+		}
+		if val.UnbondingHeight == 0 {
+			return fmt.Errorf("%w, need to unbond first", ErrValidatorNotUnbonded)
+		}
+		info, err := w.client.GetBlockchainInfo()
+		if err != nil {
+			return err
+		}
+		height := w.withdrawHeight(val.UnbondingHeight)
+		if info.LastBlockHeight+1 < height {
+			return fmt.Errorf("%w, unbonding period ends at height %v", ErrValidatorNotUnbonded, height)
+		}
+	}
+
+	return nil
+}
+
+func (w *Wallet) checkFee(trx *tx.Tx) error {
+	if trx.IsFreeTx() {
+		if trx.Fee() != 0 {
+			return fmt.Errorf("%w, expected: 0, got: %v", ErrInvalidFee, trx.Fee())
+		}
+		return nil
+	}
+	policy := w.FeePolicy()
+	if policy.Fixed {
+		fee := policy.CalcFee(trx.Payload().Value(), w.feePriority)
+		if trx.Fee() != fee {
+			return fmt.Errorf("%w, expected: %v, got: %v", ErrInvalidFee, fee, trx.Fee())
+		}
+	}
+	return nil
+}
+
+func (w *Wallet) checkAccount(trx *tx.Tx, amount int64) error {
+	acc, err := w.client.GetAccount(trx.Payload().Signer())
+	if err != nil {
+		return notFoundError(err, ErrAccountNotFound)
+	}
+	if err := w.checkSequence(trx, acc.Sequence+1); err != nil {
+		return err
+	}
+	// The pending transactions spend the balance before this one
+	balance := acc.Balance - w.pendingValue(trx.Payload().Signer(), false, trx.Sequence())
+	if balance < amount+trx.Fee() {
+		return fmt.Errorf("%w, balance: %v, required: %v", ErrInsufficientFunds, balance, amount+trx.Fee())
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func firstAddress(t *testing.T) crypto.Address {
+	for addrStr := range tWallet.Addresses() {
+		if tWallet.Role(addrStr) != RoleAccount {
+			continue
+		}
+		addr, err := crypto.AddressFromString(addrStr)
+		assert.NoError(t, err)
+		return addr
+	}
+	t.Fatal("no address in wallet")
+	return crypto.Address{}
+}
+
+func TestSimulateSendTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	stamp := hash.GenerateTestStamp()
+	server.addAccount(sender, 4, 81000)
+
+	t.Run("Ok", func(t *testing.T) {
+		trx := tx.NewSendTx(stamp, 5, sender, receiver, 80000, 1000, "")
+		bs, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.NoError(t, err)
+
+		signed, err := tx.FromBytes(bs)
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), signed.ID())
+		assert.Empty(t, server.sent, "simulation should not broadcast")
+	})
+
+	t.Run("Insufficient funds", func(t *testing.T) {
+		trx := tx.NewSendTx(stamp, 5, sender, receiver, 80001, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("Invalid fee", func(t *testing.T) {
+		trx := tx.NewSendTx(stamp, 5, sender, receiver, 1, 1001, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrInvalidFee)
+	})
+
+	t.Run("Invalid sequence", func(t *testing.T) {
+		trx := tx.NewSendTx(stamp, 4, sender, receiver, 1, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrInvalidSequence)
+	})
+
+	t.Run("Signer is not in wallet", func(t *testing.T) {
+		trx := tx.NewSendTx(stamp, 1, receiver, sender, 1, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrAddressNotFound)
+	})
+
+	t.Run("Unknown account", func(t *testing.T) {
+		delete(server.accounts, sender)
+		trx := tx.NewSendTx(stamp, 5, sender, receiver, 1, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+	})
+
+	t.Run("Node is not available", func(t *testing.T) {
+		tWallet.client = newNoServerClient("node is down")
+		trx := tx.NewSendTx(stamp, 5, sender, receiver, 1, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrAccountNotFound)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestSimulateValidatorTxs(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	addr := firstAddress(t)
+	stamp := hash.GenerateTestStamp()
+	valPub, _ := bls.GenerateTestKeyPair()
+
+	t.Run("Bond to unbonded validator", func(t *testing.T) {
+		server.addAccount(addr, 0, 1000000)
+		server.addValidator(valPub.Address(), 0, 0, 10)
+		trx := tx.NewBondTx(stamp, 1, addr, valPub, 1000, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrValidatorUnbonded)
+	})
+
+	t.Run("Unbond unknown validator", func(t *testing.T) {
+		trx := tx.NewUnbondTx(stamp, 1, addr, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrValidatorNotFound)
+	})
+
+	t.Run("Unbond", func(t *testing.T) {
+		server.addValidator(addr, 2, 5000, 0)
+		trx := tx.NewUnbondTx(stamp, 3, addr, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Withdraw from bonded validator", func(t *testing.T) {
+		trx := tx.NewWithdrawTx(stamp, 3, addr, crypto.GenerateTestAddress(), 1000, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrValidatorNotUnbonded)
+	})
+
+	t.Run("Withdraw before unbonding period", func(t *testing.T) {
+		server.addValidator(addr, 3, 5000, server.height)
+		trx := tx.NewWithdrawTx(stamp, 4, addr, crypto.GenerateTestAddress(), 1000, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.ErrorIs(t, err, ErrValidatorNotUnbonded)
+	})
+
+	t.Run("Withdraw", func(t *testing.T) {
+		server.addValidator(addr, 3, 5000, 1)
+		server.height = 1000000
+		trx := tx.NewWithdrawTx(stamp, 4, addr, crypto.GenerateTestAddress(), 4000, 1000, "")
+		_, err := tWallet.SimulateTx(tPassphrase, trx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMakeTxPreflight(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 0, 50000)
+	tWallet.SetRawUnits(true)
+
+	t.Run("Ok", func(t *testing.T) {
+		trx, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1000", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), trx.Sequence())
+		assert.Equal(t, server.lastHash.Stamp(), trx.Stamp())
+	})
+
+	t.Run("Insufficient funds", func(t *testing.T) {
+		_, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "49001", "", "", false)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("Forced", func(t *testing.T) {
+		_, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "49001", "", "", true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid receiver", func(t *testing.T) {
+		_, err := tWallet.MakeSendTx("", "", sender.String(), "invalid_addr", "1", "", "", true)
+		assert.ErrorIs(t, err, ErrInvalidReceiver)
+	})
+
+	t.Run("Memo too long", func(t *testing.T) {
+		memo := strings.Repeat("a", 65)
+		_, err := tWallet.MakeSendTx("", "", sender.String(), receiver.String(), "1", "", memo, true)
+		assert.ErrorIs(t, err, ErrMemoTooLong)
+	})
+
+	t.Run("Withdraw from bonded validator", func(t *testing.T) {
+		server.addValidator(sender, 0, 50000, 0)
+		_, err := tWallet.MakeWithdrawTx("", "", sender.String(), receiver.String(), "1000", "", "", false)
+		assert.ErrorIs(t, err, ErrValidatorNotUnbonded)
+	})
+}
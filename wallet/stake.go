@@ -0,0 +1,258 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// StakeMoveStep is a step of moving the stake of a validator
+type StakeMoveStep string
+
+const (
+	StakeMoveUnbond       = StakeMoveStep("unbond")        // Unbond transaction should be sent
+	StakeMoveUnbondSent   = StakeMoveStep("unbond-sent")   // Waiting for the unbond transaction to be committed
+	StakeMoveUnbonding    = StakeMoveStep("unbonding")     // Waiting for the unbonding period
+	StakeMoveWithdraw     = StakeMoveStep("withdraw")      // Withdraw transaction should be sent
+	StakeMoveWithdrawSent = StakeMoveStep("withdraw-sent") // Waiting for the withdraw transaction to be committed
+	StakeMoveBond         = StakeMoveStep("bond")          // Bond transaction should be sent
+	StakeMoveBondSent     = StakeMoveStep("bond-sent")     // Waiting for the bond transaction to be committed
+	StakeMoveDone         = StakeMoveStep("done")
+)
+
+// StakeMove is the state of moving the stake of a validator:
+// unbonding the validator, withdrawing the stake to an account after the
+// unbonding period, and optionally bonding it to another validator.
+// It is saved in a file, so it can be resumed after a restart.
+type StakeMove struct {
+	Validator string `json:"validator"`
+	Account   string `json:"account"`
+	// BondTo is the public key of the new validator, it is empty if the stake
+	// should remain in the account
+	BondTo         string        `json:"bond_to,omitempty"`
+	Step           StakeMoveStep `json:"step"`
+	UnbondTxID     string        `json:"unbond_tx_id,omitempty"`
+	WithdrawHeight int32         `json:"withdraw_height,omitempty"`
+	WithdrawTxID   string        `json:"withdraw_tx_id,omitempty"`
+	Withdrawn      Amount        `json:"withdrawn,omitempty"`
+	BondTxID       string        `json:"bond_tx_id,omitempty"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// NewStakeMove creates a new stake move
+func NewStakeMove(validator, account, bondTo string) (*StakeMove, error) {
+	if _, err := crypto.AddressFromString(validator); err != nil {
+		return nil, err
+	}
+	if _, err := crypto.AddressFromString(account); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceiver, err)
+	}
+	// The bond is the last step, so the public key is checked before unbonding
+	if bondTo != "" {
+		if _, err := bls.PublicKeyFromString(bondTo); err != nil {
+			return nil, fmt.Errorf("%w: invalid public key of the new validator: %v", ErrInvalidReceiver, err)
+		}
+	}
+	return &StakeMove{
+		Validator: validator,
+		Account:   account,
+		BondTo:    bondTo,
+		Step:      StakeMoveUnbond,
+		UpdatedAt: time.Now().Round(time.Second).UTC(),
+	}, nil
+}
+
+// LoadStakeMove loads a stake move from the state file
+func LoadStakeMove(path string) (*StakeMove, error) {
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := new(StakeMove)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save saves the stake move into the state file
+func (m *StakeMove) Save(path string) error {
+	m.UpdatedAt = time.Now().Round(time.Second).UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFile(path, data)
+}
+
+// IsDone returns true if all the steps are done
+func (m *StakeMove) IsDone() bool {
+	return m.Step == StakeMoveDone
+}
+
+// Describe describes the current step
+func (m *StakeMove) Describe() string {
+	switch m.Step {
+	case StakeMoveUnbond:
+		return fmt.Sprintf("unbonding validator %s", m.Validator)
+	case StakeMoveUnbondSent:
+		return fmt.Sprintf("waiting for unbond transaction %s to be committed", m.UnbondTxID)
+	case StakeMoveUnbonding:
+		return fmt.Sprintf("waiting for the unbonding period, the stake can be withdrawn at height %v", m.WithdrawHeight)
+	case StakeMoveWithdraw:
+		return fmt.Sprintf("withdrawing the stake to %s", m.Account)
+	case StakeMoveWithdrawSent:
+		return fmt.Sprintf("waiting for withdraw transaction %s to be committed", m.WithdrawTxID)
+	case StakeMoveBond:
+		return fmt.Sprintf("bonding %s to validator %s", m.Withdrawn, m.BondTo)
+	case StakeMoveBondSent:
+		return fmt.Sprintf("waiting for bond transaction %s to be committed", m.BondTxID)
+	case StakeMoveDone:
+		return "done"
+	}
+	return fmt.Sprintf("unknown step: %s", m.Step)
+}
+
+// AdvanceStakeMove runs the steps of the stake move, as far as possible.
+// It returns false if it has to wait for the blockchain to move to the next step.
+// The state should be saved after calling it, even if it fails.
+func (w *Wallet) AdvanceStakeMove(passphrase string, m *StakeMove) (bool, error) {
+	progressed := false
+	for !m.IsDone() {
+		ok, err := w.stakeMoveStep(passphrase, m)
+		if err != nil {
+			return progressed, err
+		}
+		if !ok {
+			return progressed, nil
+		}
+		progressed = true
+	}
+	return progressed, nil
+}
+
+// stakeMoveStep runs the current step.
+// It returns false if the step can't be done yet.
+func (w *Wallet) stakeMoveStep(passphrase string, m *StakeMove) (bool, error) {
+	switch m.Step {
+	case StakeMoveUnbond:
+		status, err := w.GetValidatorStatus(m.Validator)
+		if err != nil {
+			return false, err
+		}
+		if status.UnbondingHeight > 0 {
+			// Already unbonded
+			m.Step = StakeMoveUnbonding
+			return true, nil
+		}
+		trx, err := w.MakeUnbondTx("", "", m.Validator, "", false)
+		if err != nil {
+			return false, err
+		}
+		m.UnbondTxID, err = w.SignAndBroadcast(passphrase, trx)
+		if err != nil {
+			return false, err
+		}
+		m.Step = StakeMoveUnbondSent
+
+	case StakeMoveUnbondSent:
+		return w.waitStakeMoveTx(m, m.UnbondTxID, StakeMoveUnbonding, StakeMoveUnbond)
+
+	case StakeMoveUnbonding:
+		status, err := w.GetValidatorStatus(m.Validator)
+		if err != nil {
+			return false, err
+		}
+		m.WithdrawHeight = status.WithdrawHeight
+		if !status.CanWithdraw {
+			return false, nil
+		}
+		m.Step = StakeMoveWithdraw
+
+	case StakeMoveWithdraw:
+		status, err := w.GetValidatorStatus(m.Validator)
+		if err != nil {
+			return false, err
+		}
+		amount, _, err := w.sweepAmount(int64(status.Stake))
+		if err != nil {
+			return false, err
+		}
+		trx, err := w.MakeWithdrawTx("", "", m.Validator, m.Account, Amount(amount).Format(w.rawUnits), "", "", false)
+		if err != nil {
+			return false, err
+		}
+		m.WithdrawTxID, err = w.SignAndBroadcast(passphrase, trx)
+		if err != nil {
+			return false, err
+		}
+		m.Withdrawn = Amount(amount)
+		m.Step = StakeMoveWithdrawSent
+
+	case StakeMoveWithdrawSent:
+		next := StakeMoveDone
+		if m.BondTo != "" {
+			next = StakeMoveBond
+		}
+		return w.waitStakeMoveTx(m, m.WithdrawTxID, next, StakeMoveWithdraw)
+
+	case StakeMoveBond:
+		stake, _, err := w.sweepAmount(int64(m.Withdrawn))
+		if err != nil {
+			return false, err
+		}
+		trx, err := w.MakeBondTx("", "", m.Account, m.BondTo, Amount(stake).Format(w.rawUnits), "", "", false)
+		if err != nil {
+			return false, err
+		}
+		m.BondTxID, err = w.SignAndBroadcast(passphrase, trx)
+		if err != nil {
+			return false, err
+		}
+		m.Step = StakeMoveBondSent
+
+	case StakeMoveBondSent:
+		return w.waitStakeMoveTx(m, m.BondTxID, StakeMoveDone, StakeMoveBond)
+
+	default:
+		return false, fmt.Errorf("unknown step: %s", m.Step)
+	}
+
+	return true, nil
+}
+
+// waitStakeMoveTx moves to the next step if the transaction is committed.
+// If the transaction is expired, it moves back to the given step to send it again.
+func (w *Wallet) waitStakeMoveTx(m *StakeMove, idStr string, next, retry StakeMoveStep) (bool, error) {
+	id, err := hash.FromString(idStr)
+	if err != nil {
+		return false, err
+	}
+	_, err = w.client.GetTransaction(id)
+	if err == nil {
+		m.Step = next
+		return true, nil
+	}
+	if !errors.Is(err, ErrTxNotFound) {
+		return false, err
+	}
+
+	pending, err := w.PendingTxs()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range pending {
+		if p.ID == id.String() && !p.Expired {
+			return false, nil
+		}
+	}
+	// The transaction is expired or rejected
+	m.Step = retry
+	return true, nil
+}
@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// commitLastTx commits the last sent transaction in the mock server
+func (s *mockServer) commitLastTx() *tx.Tx {
+	trx := s.sent[len(s.sent)-1]
+	s.txs[trx.ID()] = trx
+	return trx
+}
+
+func TestStakeMove(t *testing.T) {
+	// The testnet has shorter intervals than the default params
+	w, err := CreateWallet(util.TempFilePath(), "", int(Testnet))
+	assert.NoError(t, err)
+	_, err = w.NewAddress("", "addr-1")
+	assert.NoError(t, err)
+	tWallet, tPassphrase = w, ""
+	server := setupMockServer(t)
+	params := tWallet.params()
+
+	valStr, err := tWallet.NewValidatorAddress(tPassphrase, "val-1")
+	assert.NoError(t, err)
+	val, _ := crypto.AddressFromString(valStr)
+	acc := firstAddress(t)
+	newVal, _ := bls.GenerateTestKeyPair()
+
+	server.addValidator(val, 1, 10*UnitsPerCoin, 0)
+	server.addAccount(acc, 0, 0)
+
+	m, err := NewStakeMove(valStr, acc.String(), newVal.String())
+	assert.NoError(t, err)
+	statePath := util.TempFilePath()
+
+	t.Run("Unbond", func(t *testing.T) {
+		progressed, err := tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.True(t, progressed)
+		assert.Equal(t, StakeMoveUnbondSent, m.Step)
+		assert.Len(t, server.sent, 1)
+		assert.True(t, server.sent[0].IsUnbondTx())
+
+		// Waiting for the unbond transaction
+		progressed, err = tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.False(t, progressed)
+		assert.NoError(t, m.Save(statePath))
+	})
+
+	t.Run("Resume after restart", func(t *testing.T) {
+		loaded, err := LoadStakeMove(statePath)
+		assert.NoError(t, err)
+		assert.Equal(t, m.Step, loaded.Step)
+		assert.Equal(t, m.UnbondTxID, loaded.UnbondTxID)
+		m = loaded
+	})
+
+	t.Run("Unbonding period", func(t *testing.T) {
+		server.commitLastTx()
+		server.validators[val].Sequence = 2
+		server.validators[val].UnbondingHeight = server.height
+
+		progressed, err := tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.True(t, progressed)
+		assert.Equal(t, StakeMoveUnbonding, m.Step)
+		assert.Equal(t, server.height+params.UnbondInterval, m.WithdrawHeight)
+	})
+
+	t.Run("Withdraw", func(t *testing.T) {
+		server.height += params.UnbondInterval
+
+		_, err := tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.Equal(t, StakeMoveWithdrawSent, m.Step)
+		assert.Len(t, server.sent, 2)
+		trx := server.sent[1]
+		assert.True(t, trx.IsWithdrawTx())
+		assert.Equal(t, int64(10*UnitsPerCoin), trx.Payload().Value()+trx.Fee())
+		assert.Equal(t, Amount(trx.Payload().Value()), m.Withdrawn)
+	})
+
+	t.Run("Withdraw is expired", func(t *testing.T) {
+		server.height += params.TransactionToLiveInterval + 1
+
+		_, err := tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.Equal(t, StakeMoveWithdrawSent, m.Step)
+		assert.Len(t, server.sent, 3)
+		assert.Equal(t, server.sent[1].Sequence(), server.sent[2].Sequence())
+	})
+
+	t.Run("Bond", func(t *testing.T) {
+		trx := server.commitLastTx()
+		server.validators[val].Stake = 0
+		server.validators[val].Sequence = trx.Sequence()
+		server.accounts[acc].Balance = trx.Payload().Value()
+
+		_, err := tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.Equal(t, StakeMoveBondSent, m.Step)
+		bondTx := server.sent[len(server.sent)-1]
+		assert.True(t, bondTx.IsBondTx())
+		assert.Equal(t, int64(m.Withdrawn), bondTx.Payload().Value()+bondTx.Fee())
+
+		server.commitLastTx()
+		_, err = tWallet.AdvanceStakeMove(tPassphrase, m)
+		assert.NoError(t, err)
+		assert.True(t, m.IsDone())
+	})
+}
+
+func TestNewStakeMove(t *testing.T) {
+	_, err := NewStakeMove("invalid", crypto.GenerateTestAddress().String(), "")
+	assert.Error(t, err)
+
+	_, err = NewStakeMove(crypto.GenerateTestAddress().String(), "invalid", "")
+	assert.ErrorIs(t, err, ErrInvalidReceiver)
+
+	_, err = NewStakeMove(crypto.GenerateTestAddress().String(), crypto.GenerateTestAddress().String(), "invalid")
+	assert.ErrorIs(t, err, ErrInvalidReceiver)
+
+	pub, _ := bls.GenerateTestKeyPair()
+	_, err = NewStakeMove(crypto.GenerateTestAddress().String(), crypto.GenerateTestAddress().String(), pub.String())
+	assert.NoError(t, err)
+}
@@ -23,6 +23,15 @@ type Store struct {
 	Encrypted bool      `json:"encrypted"`
 	VaultCRC  uint32    `json:"crc"`
 	Vault     *vault    `json:"vault"`
+	// Pending transactions are not part of the vault, they are not protected by the CRC
+	Pending []PendingTx `json:"pending,omitempty"`
+	// Multisig accounts have no private key, they are not part of the vault
+	Multisig []MultisigAccount `json:"multisig,omitempty"`
+	// Contacts are external addresses, they are not part of the vault
+	Contacts []Contact `json:"contacts,omitempty"`
+	// ChainID is the hash of the first block of the chain that the wallet is used on,
+	// if the network has no pinned genesis hash. It is not part of the vault
+	ChainID string `json:"chain_id,omitempty"`
 }
 
 type vault struct {
@@ -31,11 +40,29 @@ type vault struct {
 	Keystore  keystore  `json:"keystore"`
 }
 
+const (
+	// RoleAccount is the role of the addresses that hold coins
+	RoleAccount = "account"
+	// RoleValidator is the role of the addresses that are used by validators
+	RoleValidator = "validator"
+)
+
 type address struct {
 	Method  string `json:"method"`
 	Address string `json:"address"`
 	Label   string `json:"label"`
 	Params  params `json:"params"`
+	// Role is empty for accounts, to keep the vault of the old wallets unchanged
+	Role string `json:"role,omitempty"`
+	// Archived addresses keep their keys, they are only hidden from the listings
+	Archived bool `json:"archived,omitempty"`
+}
+
+func (a *address) role() string {
+	if a.Role == "" {
+		return RoleAccount
+	}
+	return a.Role
 }
 
 type seed struct {
@@ -49,7 +76,13 @@ type keystore struct {
 }
 
 func RecoverStore(mnemonic string, net int) (*Store, error) {
-	return createStoreFromMnemonic("", mnemonic, net)
+	return createStoreFromMnemonic("", mnemonic, net, storeVersion)
+}
+
+// RecoverLegacyStore recovers the store of a wallet created before the store
+// version 2, that uses the mainnet keys on all the networks.
+func RecoverLegacyStore(mnemonic string, net int) (*Store, error) {
+	return createStoreFromMnemonic("", mnemonic, net, legacyStoreVersion)
 }
 
 func NewStore(passphrase string, net int) (*Store, error) {
@@ -57,11 +90,21 @@ func NewStore(passphrase string, net int) (*Store, error) {
 	exitOnErr(err)
 	mnemonic, err := bip39.NewMnemonic(entropy)
 	exitOnErr(err)
-	return createStoreFromMnemonic(passphrase, mnemonic, net)
+	return createStoreFromMnemonic(passphrase, mnemonic, net, storeVersion)
 }
 
-func createStoreFromMnemonic(passphrase string, mnemonic string, net int) (*Store, error) {
-	keyInfo := []byte{} // TODO, update for testnet
+// storeVersion is the version of the new stores.
+// Version 2 separates the keys of the networks.
+const storeVersion = 2
+
+// legacyStoreVersion is the version of the stores before the network-separated keys
+const legacyStoreVersion = 1
+
+func createStoreFromMnemonic(passphrase string, mnemonic string, net int, version int) (*Store, error) {
+	if !Network(net).isValid() {
+		return nil, ErrInvalidNetwork
+	}
+	keyInfo := Network(net).keyInfo(version)
 	parentSeed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
 	if err != nil {
 		return nil, err
@@ -72,7 +115,7 @@ func createStoreFromMnemonic(passphrase string, mnemonic string, net int) (*Stor
 
 	e := newEncrypter(passphrase, net)
 	s := &Store{
-		Version:   1,
+		Version:   version,
 		UUID:      uuid.New(),
 		CreatedAt: time.Now().Round(time.Second).UTC(),
 		Network:   net,
@@ -103,6 +146,46 @@ func (s *Store) Addresses() map[string]string {
 	return addrs
 }
 
+// AddressInfos returns the information of the addresses in the order they
+// are created or imported
+func (s *Store) AddressInfos() []AddressInfo {
+	infos := make([]AddressInfo, 0, len(s.Vault.Addresses))
+	for i, a := range s.Vault.Addresses {
+		infos = append(infos, AddressInfo{
+			Index:    i,
+			Address:  a.Address,
+			Label:    a.Label,
+			Method:   a.Method,
+			Role:     a.role(),
+			Archived: a.Archived,
+		})
+	}
+
+	return infos
+}
+
+func (s *Store) ValidatorAddresses() map[string]string {
+	addrs := make(map[string]string)
+	for _, a := range s.Vault.Addresses {
+		if a.role() == RoleValidator {
+			addrs[a.Address] = a.Label
+		}
+	}
+
+	return addrs
+}
+
+func (s *Store) ImportedAddresses() []string {
+	addrs := []string{}
+	for _, a := range s.Vault.Addresses {
+		if a.Method == "IMPORTED" {
+			addrs = append(addrs, a.Address)
+		}
+	}
+
+	return addrs
+}
+
 func (s *Store) ImportPrivateKey(passphrase string, prv *bls.PrivateKey) error {
 	/// Decrypt parnet key to make sure the passphrase is correct
 	_, err := s.parentKey(passphrase)
@@ -172,7 +255,11 @@ func (s *Store) derivePrivateKey(passphrase string, keySeed []byte) (*bls.Privat
 		return nil, err
 	}
 
-	keyInfo := []byte{} // TODO, update for testnet
+	return s.deriveKey(parentKey, keySeed), nil
+}
+
+func (s *Store) deriveKey(parentKey, keySeed []byte) *bls.PrivateKey {
+	keyInfo := Network(s.Network).keyInfo(s.Version)
 
 	// To derive a new key, we need:
 	//    1- Parent Key
@@ -180,14 +267,14 @@ func (s *Store) derivePrivateKey(passphrase string, keySeed []byte) (*bls.Privat
 	//
 
 	hmac512 := hmac.New(sha512.New, parentKey)
-	_, err = hmac512.Write(keySeed)
+	_, err := hmac512.Write(keySeed)
 	exitOnErr(err)
 	ikm := hmac512.Sum(nil)
 
 	prv, err := bls.PrivateKeyFromSeed(ikm, keyInfo)
 	exitOnErr(err)
 
-	return prv, nil
+	return prv
 }
 
 func (s *Store) PrivateKey(passphrase, addr string) (*bls.PrivateKey, error) {
@@ -216,7 +303,42 @@ func (s *Store) PrivateKey(passphrase, addr string) (*bls.PrivateKey, error) {
 	return nil, ErrAddressNotFound
 }
 
+// PublicKeys returns the public keys of all the addresses.
+// The parent key is decrypted once, instead of once per address.
+func (s *Store) PublicKeys(passphrase string) (map[string]string, error) {
+	parentKey, err := s.parentKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	e := newEncrypter(passphrase, s.Network)
+	pubs := make(map[string]string)
+	for _, a := range s.Vault.Addresses {
+		switch a.Method {
+		case "IMPORTED":
+			prvStr, err := e.decrypt(s.Vault.Keystore.Prv[a.Params.GetUint32("index")])
+			exitOnErr(err)
+			prv, err := bls.PrivateKeyFromString(prvStr)
+			exitOnErr(err)
+			pubs[a.Address] = prv.PublicKey().String()
+		case "KDF-CHAIN":
+			prv := s.deriveKey(parentKey, a.Params.GetBytes("seed"))
+			pubs[a.Address] = prv.PublicKey().String()
+		}
+	}
+
+	return pubs, nil
+}
+
 func (s *Store) NewAddress(passphrase, label string) (string, error) {
+	return s.newAddress(passphrase, label, "")
+}
+
+func (s *Store) NewValidatorAddress(passphrase, label string) (string, error) {
+	return s.newAddress(passphrase, label, RoleValidator)
+}
+
+func (s *Store) newAddress(passphrase, label, role string) (string, error) {
 	keySeed, err := s.newKeySeed(passphrase)
 	if err != nil {
 		return "", err
@@ -233,6 +355,7 @@ func (s *Store) NewAddress(passphrase, label string) (string, error) {
 		Address: prv.PublicKey().Address().String(),
 		Label:   label,
 		Params:  params,
+		Role:    role,
 	}
 
 	s.Vault.Addresses = append(s.Vault.Addresses, a)
@@ -253,6 +376,57 @@ func (s *Store) getAddressInfo(addr crypto.Address) *address {
 	return nil
 }
 
+func (s *Store) Label(addr string) string {
+	for _, a := range s.Vault.Addresses {
+		if a.Address == addr {
+			return a.Label
+		}
+	}
+	return ""
+}
+
+func (s *Store) SetLabel(addr, label string) error {
+	a := s.findAddress(addr)
+	if a == nil {
+		return ErrAddressNotFound
+	}
+	a.Label = label
+	return nil
+}
+
+func (s *Store) SetArchived(addr string, archived bool) error {
+	a := s.findAddress(addr)
+	if a == nil {
+		return ErrAddressNotFound
+	}
+	a.Archived = archived
+	return nil
+}
+
+func (s *Store) IsArchived(addr string) bool {
+	a := s.findAddress(addr)
+	return a != nil && a.Archived
+}
+
+// findAddress returns a pointer to the address in the vault, so it can be updated
+func (s *Store) findAddress(addr string) *address {
+	for i := range s.Vault.Addresses {
+		if s.Vault.Addresses[i].Address == addr {
+			return &s.Vault.Addresses[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) Role(addr string) string {
+	for _, a := range s.Vault.Addresses {
+		if a.Address == addr {
+			return a.role()
+		}
+	}
+	return ""
+}
+
 func (s *Store) Mnemonic(passphrase string) (string, error) {
 	return newEncrypter(passphrase, s.Network).decrypt(s.Vault.Seed.ParentSeed)
 }
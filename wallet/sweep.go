@@ -0,0 +1,72 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/tx"
+)
+
+// MakeSweepTx creates a send transaction that transfers the whole balance of
+// the sender, minus the fee and the pending transactions, to the receiver.
+// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeSweepTx(stampStr, seqStr, senderStr, receiverStr, memo string, force bool) (*tx.Tx, error) {
+	if err := checkMemo(memo); err != nil {
+		return nil, err
+	}
+	sender, err := crypto.AddressFromString(senderStr)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := crypto.AddressFromString(receiverStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceiver, err)
+	}
+	balance, err := w.client.GetAccountBalance(sender)
+	if err != nil {
+		return nil, notFoundError(err, ErrAccountNotFound)
+	}
+	stamp, err := w.parsStamp(stampStr)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := w.parsAccSeq(sender, seqStr)
+	if err != nil {
+		return nil, err
+	}
+	// The pending transactions spend the balance before this one
+	balance -= w.pendingValue(sender, false, seq)
+	amount, fee, err := w.sweepAmount(balance)
+	if err != nil {
+		return nil, err
+	}
+
+	trx := tx.NewSendTx(stamp, seq, sender, receiver, amount, fee, memo)
+	return w.preflightCheck(trx, force)
+}
+
+// sweepAmount finds the largest amount that can be sent from the balance,
+// paying the fee. Since the fee depends on the amount, the remaining balance
+// might be one base unit in some cases.
+func (w *Wallet) sweepAmount(balance int64) (int64, int64, error) {
+	if balance <= w.CalcFee(0) {
+		return 0, 0, fmt.Errorf("%w, balance: %v, minimum fee: %v", ErrInsufficientFunds, balance, w.CalcFee(0))
+	}
+
+	// The fee is increasing by the amount, so we can use binary search
+	low, high := int64(0), balance
+	for low < high {
+		mid := low + (high-low+1)/2
+		if mid+w.CalcFee(mid) <= balance {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, w.CalcFee(low), nil
+}
+
+// ImportedAddresses returns the addresses of the imported private keys
+func (w *Wallet) ImportedAddresses() []string {
+	return w.store.ImportedAddresses()
+}
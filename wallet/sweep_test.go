@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/tx/payload"
+)
+
+func TestSweepAmount(t *testing.T) {
+	setup(t)
+
+	balances := []int64{1001, 2000, 1000999, 1001000, 1001001, 1002003, 123456789012, 2100000000000000}
+	for _, balance := range balances {
+		amount, fee, err := tWallet.sweepAmount(balance)
+		assert.NoError(t, err)
+		assert.Equal(t, tWallet.CalcFee(amount), fee)
+		assert.LessOrEqual(t, amount+fee, balance)
+		assert.GreaterOrEqual(t, amount+fee, balance-1, "balance: %v", balance)
+		assert.Greater(t, amount+1+tWallet.CalcFee(amount+1), balance)
+	}
+
+	_, _, err := tWallet.sweepAmount(1000)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestMakeSweepTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	sender := firstAddress(t)
+	receiver := crypto.GenerateTestAddress()
+	server.addAccount(sender, 7, 5000000)
+
+	trx, err := tWallet.MakeSweepTx("", "", sender.String(), receiver.String(), "", false)
+	assert.NoError(t, err)
+	pld := trx.Payload().(*payload.SendPayload)
+	assert.Equal(t, int64(5000000), pld.Amount+trx.Fee())
+	assert.Equal(t, int32(8), trx.Sequence())
+
+	t.Run("Pending transactions", func(t *testing.T) {
+		_, err := tWallet.SignAndBroadcast(tPassphrase, trx)
+		assert.NoError(t, err)
+
+		_, err = tWallet.MakeSweepTx("", "", sender.String(), receiver.String(), "", false)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+		// The node hasn't committed the pending transaction yet
+		server.addAccount(sender, 7, 8000000)
+		trx, err := tWallet.MakeSweepTx("", "", sender.String(), receiver.String(), "", false)
+		assert.NoError(t, err)
+		pld := trx.Payload().(*payload.SendPayload)
+		assert.Equal(t, int64(3000000), pld.Amount+trx.Fee())
+		assert.Equal(t, int32(9), trx.Sequence())
+	})
+
+	t.Run("Empty account", func(t *testing.T) {
+		server.addAccount(sender, 8, 0)
+		_, err := tWallet.MakeSweepTx("", "", sender.String(), receiver.String(), "", false)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+}
+
+func TestImportedAddresses(t *testing.T) {
+	setup(t)
+	assert.Empty(t, tWallet.ImportedAddresses())
+
+	_, prv := bls.GenerateTestKeyPair()
+	assert.NoError(t, tWallet.ImportPrivateKey(tPassphrase, prv.String()))
+	assert.Equal(t, []string{prv.PublicKey().Address().String()}, tWallet.ImportedAddresses())
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/sortition"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+	zarb "github.com/zarbchain/zarb-go/www/grpc/proto"
+)
+
+// GetTransaction fetches a committed transaction from the node and decodes it
+func (w *Wallet) GetTransaction(idStr string) (*tx.Tx, error) {
+	id, err := hash.FromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+	info, err := w.client.GetTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	trx, err := txFromInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	if !trx.ID().EqualsTo(id) {
+		return nil, fmt.Errorf("transaction ID mismatch, expected: %v, got: %v", id, trx.ID())
+	}
+	return trx, nil
+}
+
+// DecodeTx decodes a raw transaction. The data can be hex encoded or in bytes.
+// It doesn't verify the transaction, use SanityCheck to verify it.
+func DecodeTx(data []byte) (*tx.Tx, error) {
+	bs, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		bs = data
+	}
+	return tx.FromBytes(bs)
+}
+
+// DecodeUnsignedTx decodes a transaction without the signature and the public key.
+// The data can be hex encoded or in bytes.
+func DecodeUnsignedTx(data []byte) (*tx.Tx, error) {
+	bs, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		bs = data
+	}
+	trx := new(tx.Tx)
+	if err := trx.DecodeWithNoSignatory(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+// txFromInfo rebuilds a transaction from the node's response.
+// The node doesn't return the payload of unbond and withdraw transactions.
+// The unbond payload can be recovered from the public key,
+// but the withdraw payload can't.
+func txFromInfo(info *zarb.TransactionInfo) (*tx.Tx, error) {
+	stamp := hash.Stamp{}
+	if len(info.Stamp) != hash.StampSize {
+		return nil, fmt.Errorf("invalid stamp length: %v", len(info.Stamp))
+	}
+	copy(stamp[:], info.Stamp)
+
+	var pub *bls.PublicKey
+	var sig *bls.Signature
+	if len(info.PublicKey) > 0 {
+		var err error
+		pub, err = bls.PublicKeyFromBytes(info.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		sig, err = bls.SignatureFromBytes(info.Signature)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pld payload.Payload
+	switch payload.Type(info.Type) {
+	case payload.PayloadTypeSend:
+		p := info.GetSend()
+		if p == nil {
+			return nil, errors.New("send payload is missing")
+		}
+		sender, err := crypto.AddressFromString(p.Sender)
+		if err != nil {
+			return nil, err
+		}
+		receiver, err := crypto.AddressFromString(p.Receiver)
+		if err != nil {
+			return nil, err
+		}
+		pld = &payload.SendPayload{Sender: sender, Receiver: receiver, Amount: p.Amount}
+
+	case payload.PayloadTypeBond:
+		p := info.GetBond()
+		if p == nil {
+			return nil, errors.New("bond payload is missing")
+		}
+		sender, err := crypto.AddressFromString(p.Sender)
+		if err != nil {
+			return nil, err
+		}
+		valPub, err := bls.PublicKeyFromString(p.Validator)
+		if err != nil {
+			return nil, err
+		}
+		pld = &payload.BondPayload{Sender: sender, PublicKey: valPub, Stake: p.Stake}
+
+	case payload.PayloadTypeSortition:
+		p := info.GetSortition()
+		if p == nil {
+			return nil, errors.New("sortition payload is missing")
+		}
+		addr, err := crypto.AddressFromString(p.Address)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := sortition.ProofFromString(p.Proof)
+		if err != nil {
+			return nil, err
+		}
+		pld = &payload.SortitionPayload{Address: addr, Proof: proof}
+
+	case payload.PayloadTypeUnbond:
+		if pub == nil {
+			return nil, errors.New("public key is missing")
+		}
+		pld = &payload.UnbondPayload{Validator: pub.Address()}
+
+	default:
+		return nil, fmt.Errorf("unable to decode %v payload", payload.Type(info.Type))
+	}
+
+	trx := tx.NewTx(stamp, info.Sequence, pld, info.Fee, info.Memo)
+	if pub != nil {
+		trx.SetPublicKey(pub)
+		trx.SetSignature(sig)
+	}
+	return trx, nil
+}
@@ -0,0 +1,139 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/tx/payload"
+	zarb "github.com/zarbchain/zarb-go/www/grpc/proto"
+)
+
+// txToInfo converts a transaction the same way the node does
+func txToInfo(trx *tx.Tx) *zarb.TransactionInfo {
+	info := &zarb.TransactionInfo{
+		Id:        trx.ID().Bytes(),
+		Version:   int32(trx.Version()),
+		Stamp:     trx.Stamp().Bytes(),
+		Sequence:  trx.Sequence(),
+		Fee:       trx.Fee(),
+		Type:      zarb.PayloadType(trx.Payload().Type()),
+		Memo:      trx.Memo(),
+		PublicKey: trx.PublicKey().Bytes(),
+		Signature: trx.Signature().Bytes(),
+	}
+
+	switch pld := trx.Payload().(type) {
+	case *payload.SendPayload:
+		info.Payload = &zarb.TransactionInfo_Send{Send: &zarb.SEND_PAYLOAD{
+			Sender: pld.Sender.String(), Receiver: pld.Receiver.String(), Amount: pld.Amount}}
+	case *payload.BondPayload:
+		info.Payload = &zarb.TransactionInfo_Bond{Bond: &zarb.BOND_PAYLOAD{
+			Sender: pld.Sender.String(), Validator: pld.PublicKey.String(), Stake: pld.Stake}}
+	case *payload.SortitionPayload:
+		proof, _ := pld.Proof.MarshalText()
+		info.Payload = &zarb.TransactionInfo_Sortition{Sortition: &zarb.SORTITION_PAYLOAD{
+			Address: pld.Address.String(), Proof: string(proof)}}
+	}
+	return info
+}
+
+func TestTxFromInfo(t *testing.T) {
+	trx1, _ := tx.GenerateTestSendTx()
+	trx2, _ := tx.GenerateTestBondTx()
+	trx3, _ := tx.GenerateTestSortitionTx()
+	trx4, _ := tx.GenerateTestUnbondTx()
+
+	for _, trx := range []*tx.Tx{trx1, trx2, trx3, trx4} {
+		decoded, err := txFromInfo(txToInfo(trx))
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), decoded.ID())
+		assert.NoError(t, decoded.SanityCheck())
+	}
+
+	t.Run("Withdraw payload is not provided", func(t *testing.T) {
+		trx, _ := tx.GenerateTestWithdrawTx()
+		_, err := txFromInfo(txToInfo(trx))
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid stamp", func(t *testing.T) {
+		info := txToInfo(trx1)
+		info.Stamp = []byte{1}
+		_, err := txFromInfo(info)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeTx(t *testing.T) {
+	trx, _ := tx.GenerateTestSendTx()
+	bs, _ := trx.Bytes()
+
+	t.Run("Raw bytes", func(t *testing.T) {
+		decoded, err := DecodeTx(bs)
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), decoded.ID())
+		assert.NoError(t, decoded.SanityCheck())
+	})
+
+	t.Run("Hex string", func(t *testing.T) {
+		decoded, err := DecodeTx([]byte(hex.EncodeToString(bs) + "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), decoded.ID())
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		pld := trx.Payload().(*payload.SendPayload)
+		forged := tx.NewSendTx(trx.Stamp(), trx.Sequence(), pld.Sender, pld.Receiver, pld.Amount+1, trx.Fee(), trx.Memo())
+		forged.SetPublicKey(trx.PublicKey())
+		forged.SetSignature(trx.Signature())
+		bs, _ := forged.Bytes()
+
+		decoded, err := DecodeTx(bs)
+		assert.NoError(t, err)
+		assert.Error(t, decoded.SanityCheck())
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		_, err := DecodeTx([]byte("invalid_data"))
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeUnsignedTx(t *testing.T) {
+	trx, _ := tx.GenerateTestSendTx()
+
+	decoded, err := DecodeUnsignedTx([]byte(hex.EncodeToString(trx.SignBytes())))
+	assert.NoError(t, err)
+	assert.Equal(t, trx.ID(), decoded.ID())
+	assert.Nil(t, decoded.Signature())
+
+	_, err = DecodeUnsignedTx([]byte("invalid_data"))
+	assert.Error(t, err)
+}
+
+func TestGetTransaction(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+
+	trx, _ := tx.GenerateTestSendTx()
+	server.txs[trx.ID()] = trx
+
+	t.Run("Ok", func(t *testing.T) {
+		fetched, err := tWallet.GetTransaction(trx.ID().String())
+		assert.NoError(t, err)
+		assert.Equal(t, trx.ID(), fetched.ID())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := tWallet.GetTransaction(hash.GenerateTestHash().String())
+		assert.ErrorIs(t, err, ErrTxNotFound)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		_, err := tWallet.GetTransaction("invalid_id")
+		assert.Error(t, err)
+	})
+}
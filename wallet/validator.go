@@ -0,0 +1,116 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// ValidatorStatus is the state of a validator in the blockchain
+type ValidatorStatus struct {
+	Address           string `json:"address"`
+	PublicKey         string `json:"public_key"`
+	Number            int32  `json:"number"`
+	Sequence          int32  `json:"sequence"`
+	Stake             Amount `json:"stake"`
+	LastBondingHeight int32  `json:"last_bonding_height"`
+	LastJoinedHeight  int32  `json:"last_joined_height"` // Last time the validator joined the committee by sortition
+	UnbondingHeight   int32  `json:"unbonding_height"`
+	CurrentHeight     int32  `json:"current_height"`
+	// SortitionEligible means the validator is bonded and can join the committee
+	SortitionEligible bool `json:"sortition_eligible"`
+	// WithdrawHeight is the height that the stake can be withdrawn from it,
+	// it is zero if the validator is not unbonded
+	WithdrawHeight int32 `json:"withdraw_height"`
+	CanWithdraw    bool  `json:"can_withdraw"`
+}
+
+// NewValidatorAddress derives a new validator key from the wallet seed
+func (w *Wallet) NewValidatorAddress(passphrase, label string) (string, error) {
+	addr, err := w.store.NewValidatorAddress(passphrase, label)
+	if err != nil {
+		return "", err
+	}
+	err = w.saveToFile()
+	if err != nil {
+		return "", err
+	}
+
+	return addr, nil
+}
+
+// ValidatorAddresses returns the validator addresses and their labels
+func (w *Wallet) ValidatorAddresses() map[string]string {
+	return w.store.ValidatorAddresses()
+}
+
+// Role returns the role of the address: account or validator.
+// It returns an empty string if the address doesn't belong to the wallet.
+func (w *Wallet) Role(addrStr string) string {
+	return w.store.Role(addrStr)
+}
+
+// ExportValidatorKey writes the private key of the validator into the file,
+// in the format of the "validator_key" file of the zarb node.
+// It doesn't overwrite an existing file.
+func (w *Wallet) ExportValidatorKey(passphrase, addrStr, path string) error {
+	if w.Role(addrStr) != RoleValidator {
+		return fmt.Errorf("%w: %s", ErrNotValidatorAddress, addrStr)
+	}
+	if util.PathExists(path) {
+		return fmt.Errorf("file already exists: %s", path)
+	}
+	prv, err := w.store.PrivateKey(passphrase, addrStr)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(prv.String()), 0600)
+}
+
+// GetValidatorStatus returns the state of the validator in the blockchain
+func (w *Wallet) GetValidatorStatus(addrStr string) (*ValidatorStatus, error) {
+	addr, err := crypto.AddressFromString(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	val, err := w.client.GetValidator(addr)
+	if err != nil {
+		return nil, notFoundError(err, ErrValidatorNotFound)
+	}
+	info, err := w.client.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ValidatorStatus{
+		Address:           addr.String(),
+		Number:            val.Number,
+		Sequence:          val.Sequence,
+		Stake:             Amount(val.Stake),
+		LastBondingHeight: val.LastBondingHeight,
+		LastJoinedHeight:  val.LastJoinedHeight,
+		UnbondingHeight:   val.UnbondingHeight,
+		CurrentHeight:     info.LastBlockHeight,
+	}
+	if pub, err := bls.PublicKeyFromBytes(val.PublicKey); err == nil {
+		status.PublicKey = pub.String()
+	}
+
+	nextHeight := info.LastBlockHeight + 1
+	if val.UnbondingHeight > 0 {
+		status.WithdrawHeight = w.withdrawHeight(val.UnbondingHeight)
+		status.CanWithdraw = nextHeight >= status.WithdrawHeight && val.Stake > 0
+	} else {
+		status.SortitionEligible = nextHeight-val.LastBondingHeight >= w.params().BondInterval
+	}
+
+	return status, nil
+}
+
+// withdrawHeight returns the height that the stake of an unbonded validator can be withdrawn
+func (w *Wallet) withdrawHeight(unbondingHeight int32) int32 {
+	return unbondingHeight + w.params().UnbondInterval
+}
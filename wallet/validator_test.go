@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/bls"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/genesis"
+	"github.com/zarbchain/zarb-go/sortition"
+	"github.com/zarbchain/zarb-go/tx/payload"
+	"github.com/zarbchain/zarb-go/util"
+)
+
+func TestValidatorAddress(t *testing.T) {
+	setup(t)
+
+	addr, err := tWallet.NewValidatorAddress(tPassphrase, "val-1")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleValidator, tWallet.Role(addr))
+	assert.Equal(t, map[string]string{addr: "val-1"}, tWallet.ValidatorAddresses())
+	assert.Contains(t, tWallet.Addresses(), addr)
+
+	account := firstAddress(t).String()
+	assert.Equal(t, RoleAccount, tWallet.Role(account))
+	assert.Equal(t, "", tWallet.Role("unknown"))
+
+	reopenWallet(t)
+	assert.Equal(t, RoleValidator, tWallet.Role(addr))
+}
+
+func TestExportValidatorKey(t *testing.T) {
+	setup(t)
+
+	addr, err := tWallet.NewValidatorAddress(tPassphrase, "val-1")
+	assert.NoError(t, err)
+
+	t.Run("Not a validator", func(t *testing.T) {
+		err := tWallet.ExportValidatorKey(tPassphrase, firstAddress(t).String(), util.TempFilePath())
+		assert.ErrorIs(t, err, ErrNotValidatorAddress)
+	})
+
+	t.Run("Export the key", func(t *testing.T) {
+		path := util.TempFilePath()
+		assert.NoError(t, tWallet.ExportValidatorKey(tPassphrase, addr, path))
+
+		data, err := util.ReadFile(path)
+		assert.NoError(t, err)
+		prv, err := bls.PrivateKeyFromString(string(data))
+		assert.NoError(t, err)
+		assert.Equal(t, addr, prv.PublicKey().Address().String())
+
+		// Existing files are not overwritten
+		assert.Error(t, tWallet.ExportValidatorKey(tPassphrase, addr, path))
+	})
+}
+
+func TestGetValidatorStatus(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+	params := tWallet.params()
+
+	pub, _ := bls.GenerateTestKeyPair()
+	addr := pub.Address()
+	server.addValidator(addr, 3, 5*UnitsPerCoin, 0)
+	server.validators[addr].PublicKey = pub.Bytes()
+	server.validators[addr].LastBondingHeight = server.height - params.BondInterval + 10
+
+	t.Run("Unknown validator", func(t *testing.T) {
+		_, err := tWallet.GetValidatorStatus(crypto.GenerateTestAddress().String())
+		assert.ErrorIs(t, err, ErrValidatorNotFound)
+	})
+
+	t.Run("Bonding period is not passed", func(t *testing.T) {
+		status, err := tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.Equal(t, pub.String(), status.PublicKey)
+		assert.Equal(t, int32(3), status.Sequence)
+		assert.Equal(t, Amount(5*UnitsPerCoin), status.Stake)
+		assert.Equal(t, server.height, status.CurrentHeight)
+		assert.False(t, status.SortitionEligible)
+		assert.False(t, status.CanWithdraw)
+		assert.Zero(t, status.WithdrawHeight)
+	})
+
+	t.Run("Bonded", func(t *testing.T) {
+		server.height += 10
+		status, err := tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.True(t, status.SortitionEligible)
+	})
+
+	t.Run("Unbonding", func(t *testing.T) {
+		server.validators[addr].UnbondingHeight = server.height
+		status, err := tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.False(t, status.SortitionEligible)
+		assert.False(t, status.CanWithdraw)
+		assert.Equal(t, server.height+params.UnbondInterval, status.WithdrawHeight)
+	})
+
+	t.Run("Unbonded", func(t *testing.T) {
+		server.height += params.UnbondInterval
+		status, err := tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.True(t, status.CanWithdraw)
+	})
+
+	t.Run("Testnet params", func(t *testing.T) {
+		tWallet.store.Network = int(Testnet)
+		testnet := genesis.Testnet().Params()
+		server.validators[addr].UnbondingHeight = server.height - testnet.UnbondInterval
+		status, err := tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.Equal(t, server.height, status.WithdrawHeight)
+		assert.True(t, status.CanWithdraw)
+
+		server.validators[addr].UnbondingHeight = 0
+		server.validators[addr].LastBondingHeight = server.height - testnet.BondInterval + 1
+		status, err = tWallet.GetValidatorStatus(addr.String())
+		assert.NoError(t, err)
+		assert.True(t, status.SortitionEligible)
+	})
+}
+
+func TestMakeSortitionTx(t *testing.T) {
+	setup(t)
+	server := setupMockServer(t)
+	params := tWallet.params()
+
+	addr, err := tWallet.NewValidatorAddress(tPassphrase, "val-1")
+	assert.NoError(t, err)
+	valAddr, _ := crypto.AddressFromString(addr)
+	server.addValidator(valAddr, 4, 5*UnitsPerCoin, 0)
+	server.validators[valAddr].LastBondingHeight = server.height - params.BondInterval + 1
+	proof := sortition.GenerateRandomProof()
+	proofStr, _ := proof.MarshalText()
+
+	t.Run("Invalid proof", func(t *testing.T) {
+		_, err := tWallet.MakeSortitionTx("", "", addr, "invalid-proof", false)
+		assert.Error(t, err)
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		stamp := hash.GenerateTestHash().Stamp()
+		trx, err := tWallet.MakeSortitionTx(stamp.String(), "", addr, string(proofStr), false)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(5), trx.Sequence())
+		assert.Equal(t, stamp, trx.Stamp())
+		assert.Zero(t, trx.Fee())
+		assert.Equal(t, proof, trx.Payload().(*payload.SortitionPayload).Proof)
+
+		_, err = tWallet.SignTx(tPassphrase, trx)
+		assert.NoError(t, err)
+		assert.NoError(t, trx.SanityCheck())
+	})
+
+	t.Run("Bonding period is not passed", func(t *testing.T) {
+		server.validators[valAddr].LastBondingHeight = server.height
+		_, err := tWallet.MakeSortitionTx("", "", addr, string(proofStr), false)
+		assert.ErrorIs(t, err, ErrValidatorNotBonded)
+	})
+
+	t.Run("Testnet bond interval", func(t *testing.T) {
+		tWallet.store.Network = int(Testnet)
+		server.validators[valAddr].LastBondingHeight = server.height - genesis.Testnet().Params().BondInterval + 1
+		_, err := tWallet.MakeSortitionTx("", "", addr, string(proofStr), false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unbonded", func(t *testing.T) {
+		server.validators[valAddr].UnbondingHeight = server.height
+		_, err := tWallet.MakeSortitionTx("", "", addr, string(proofStr), false)
+		assert.ErrorIs(t, err, ErrValidatorUnbonded)
+	})
+}
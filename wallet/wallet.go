@@ -4,12 +4,14 @@ import (
 	_ "embed"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/rand"
 	"strconv"
 
 	"github.com/zarbchain/zarb-go/crypto"
 	"github.com/zarbchain/zarb-go/crypto/bls"
 	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/sortition"
 	"github.com/zarbchain/zarb-go/tx"
 	"github.com/zarbchain/zarb-go/util"
 )
@@ -19,6 +21,18 @@ var (
 	/// exists in the given path
 	ErrWalletExits = errors.New("wallet exists")
 
+	/// ErrWalletNotFound describes an error in which there is no wallet
+	/// with the given name
+	ErrWalletNotFound = errors.New("wallet not found")
+
+	/// ErrInvalidWalletName describes an error in which the wallet name
+	/// is not a file name
+	ErrInvalidWalletName = errors.New("invalid wallet name")
+
+	/// ErrNotWallet describes an error in which the file is not
+	/// a wallet file
+	ErrNotWallet = errors.New("not a wallet file")
+
 	/// ErrWalletExits describes an error in which the wallet CRC is
 	/// invalid
 	ErrInvalidCRC = errors.New("invalid CRC")
@@ -27,6 +41,18 @@ var (
 	/// valid
 	ErrInvalidNetwork = errors.New("invalid network")
 
+	/// ErrChainMismatch describes an error in which the node is on
+	/// another chain than the wallet network
+	ErrChainMismatch = errors.New("chain mismatch")
+
+	/// ErrContactExists describes an error in which a contact with
+	/// the same name or address exists
+	ErrContactExists = errors.New("contact already exists")
+
+	/// ErrContactNotFound describes an error in which the contact doesn't
+	/// exist in the address book
+	ErrContactNotFound = errors.New("contact not found")
+
 	/// ErrWalletExits describes an error in which the address doesn't
 	/// exist in wallet
 	ErrAddressNotFound = errors.New("address not found")
@@ -34,23 +60,120 @@ var (
 	/// ErrWalletExits describes an error in which the address already
 	/// exist in wallet
 	ErrAddressExists = errors.New("address already exists")
+
+	/// ErrInvalidPassphrase describes an error in which the passphrase
+	/// can't decrypt the wallet
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+
+	/// ErrTxNotFound describes an error in which the transaction is not
+	/// committed, or it is unknown to the node
+	ErrTxNotFound = errors.New("transaction not found")
+
+	/// ErrInsufficientFunds describes an error in which the account balance
+	/// or the validator stake can't cover the amount and the fee
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	/// ErrInvalidSequence describes an error in which the transaction
+	/// sequence doesn't match the signer's next sequence
+	ErrInvalidSequence = errors.New("invalid sequence")
+
+	/// ErrAccountNotFound describes an error in which the account doesn't
+	/// exist in the blockchain
+	ErrAccountNotFound = errors.New("account not found")
+
+	/// ErrValidatorNotFound describes an error in which the validator doesn't
+	/// exist in the blockchain
+	ErrValidatorNotFound = errors.New("validator not found")
+
+	/// ErrValidatorUnbonded describes an error in which the validator is
+	/// already unbonded
+	ErrValidatorUnbonded = errors.New("validator is unbonded")
+
+	/// ErrValidatorNotUnbonded describes an error in which the validator
+	/// is not unbonded, or the unbonding period is not passed yet
+	ErrValidatorNotUnbonded = errors.New("validator is not unbonded")
+
+	/// ErrInvalidReceiver describes an error in which the receiver address
+	/// has an unknown format
+	ErrInvalidReceiver = errors.New("invalid receiver address")
+
+	/// ErrMemoTooLong describes an error in which the transaction memo
+	/// is longer than the maximum memo length
+	ErrMemoTooLong = errors.New("memo is too long")
+
+	/// ErrInvalidFee describes an error in which the transaction fee
+	/// doesn't match the network fee policy
+	ErrInvalidFee = errors.New("invalid fee")
+
+	/// ErrTxNotPending describes an error in which the transaction
+	/// is not in the pending transactions of the wallet
+	ErrTxNotPending = errors.New("transaction is not pending")
+
+	/// ErrTxConfirmed describes an error in which the transaction
+	/// is already committed
+	ErrTxConfirmed = errors.New("transaction is already confirmed")
+
+	/// ErrNotValidatorAddress describes an error in which the address
+	/// is not a validator address of the wallet
+	ErrNotValidatorAddress = errors.New("not a validator address")
+
+	/// ErrValidatorNotBonded describes an error in which the bonding period
+	/// of the validator is not passed yet
+	ErrValidatorNotBonded = errors.New("validator is not bonded yet")
+
+	/// ErrThresholdNotSupported describes an error in which the threshold of
+	/// the multisig account is not supported by the protocol
+	ErrThresholdNotSupported = errors.New("only n-of-n multisig accounts are supported by the protocol")
+
+	/// ErrInvalidMember describes an error in which the member of
+	/// a multisig account is invalid
+	ErrInvalidMember = errors.New("invalid multisig member")
+
+	/// ErrMissingSignatures describes an error in which some members of
+	/// a multisig account have not signed the transaction yet
+	ErrMissingSignatures = errors.New("missing signatures")
+
+	/// ErrInvalidSignature describes an error in which the signature
+	/// of a message is invalid
+	ErrInvalidSignature = errors.New("invalid signature")
 )
 
 type Wallet struct {
-	path   string
-	store  *Store
-	client *GrpcClient
+	path        string
+	store       *Store
+	client      *GrpcClient
+	feePolicy   *FeePolicy
+	feePriority FeePriority
+	rawUnits    bool
+	// chainChecked is set when the chain of the node is checked
+	chainChecked bool
 }
 
 type serverInfo struct {
 	Name string `json:"name"`
 	IP   string `json:"ip"`
 }
-type servers = map[string][]serverInfo
+
+type networkInfo struct {
+	// GenesisHash is the hash of the first block of the network, it identifies the chain.
+	// If it is not set, the chain that the wallet sees first is recorded.
+	GenesisHash string       `json:"genesis_hash,omitempty"`
+	Servers     []serverInfo `json:"servers"`
+}
 
 //go:embed servers.json
 var serversJSON []byte
 
+// networkInfos are the known networks, by name
+var networkInfos = loadNetworkInfos()
+
+func loadNetworkInfos() map[string]networkInfo {
+	infos := make(map[string]networkInfo)
+	err := json.Unmarshal(serversJSON, &infos)
+	exitOnErr(err)
+	return infos
+}
+
 /// OpenWallet generates an empty wallet and save the seed string
 func OpenWallet(path string) (*Wallet, error) {
 	data, err := util.ReadFile(path)
@@ -71,11 +194,23 @@ func OpenWallet(path string) (*Wallet, error) {
 
 /// Recover recovers a wallet from mnemonic (seed phrase)
 func RecoverWallet(path, mnemonic string, net int) (*Wallet, error) {
+	return recoverWallet(path, mnemonic, net, RecoverStore)
+}
+
+/// RecoverLegacyWallet recovers a wallet from mnemonic (seed phrase) with the
+/// keys of the wallets before the network-separated keys.
+/// On the mainnet the keys are the same as RecoverWallet.
+func RecoverLegacyWallet(path, mnemonic string, net int) (*Wallet, error) {
+	return recoverWallet(path, mnemonic, net, RecoverLegacyStore)
+}
+
+func recoverWallet(path, mnemonic string, net int,
+	recoverStore func(mnemonic string, net int) (*Store, error)) (*Wallet, error) {
 	path = util.MakeAbs(path)
 	if util.PathExists(path) {
 		return nil, ErrWalletExits
 	}
-	s, err := RecoverStore(mnemonic, net)
+	s, err := recoverStore(mnemonic, net)
 	if err != nil {
 		return nil, err
 	}
@@ -117,8 +252,9 @@ func CreateWallet(path, passphrase string, net int) (*Wallet, error) {
 
 func newWallet(path string, store *Store, online bool) (*Wallet, error) {
 	w := &Wallet{
-		store: store,
-		path:  path,
+		store:       store,
+		path:        path,
+		feePriority: FeePriorityNormal,
 	}
 
 	err := w.connectToRandomServer()
@@ -130,25 +266,14 @@ func newWallet(path string, store *Store, online bool) (*Wallet, error) {
 }
 
 func (w *Wallet) connectToRandomServer() error {
-	serversInfo := servers{}
-	err := json.Unmarshal(serversJSON, &serversInfo)
-	exitOnErr(err)
-
-	var netServers []serverInfo
-	switch w.store.Network {
-	case 0:
-		{ // mainnet
-			netServers = serversInfo["mainnet"]
-		}
-	case 1:
-		{ // testnet
-			netServers = serversInfo["testnet"]
-		}
-
-	default:
-		{
-			return ErrInvalidNetwork
-		}
+	net := Network(w.store.Network)
+	if !net.isValid() {
+		return ErrInvalidNetwork
+	}
+	netServers := networkInfos[net.String()].Servers
+	if len(netServers) == 0 {
+		w.client = newNoServerClient(fmt.Sprintf("no server is known for the %s network", net))
+		return nil
 	}
 
 	for i := 0; i < 3; i++ {
@@ -206,18 +331,24 @@ func (w *Wallet) NewAddress(passphrase, label string) (string, error) {
 	return addr, nil
 }
 
-func (w *Wallet) GetBalance(addrStr string) (int64, int64, error) {
+/// GetBalance returns the balance and stake of the address.
+/// They are zero if the account or the validator doesn't exist.
+func (w *Wallet) GetBalance(addrStr string) (Amount, Amount, error) {
 	addr, err := crypto.AddressFromString(addrStr)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	balance, _ := w.client.GetAccountBalance(addr)
-	//exitOnErr(err)
-	stake, _ := w.client.GetValidatorStake(addr)
-	//exitOnErr(err)
+	balance, err := w.client.GetAccountBalance(addr)
+	if err != nil && !isNotFound(err) {
+		return 0, 0, err
+	}
+	stake, err := w.client.GetValidatorStake(addr)
+	if err != nil && !isNotFound(err) {
+		return 0, 0, err
+	}
 
-	return balance, stake, nil
+	return Amount(balance), Amount(stake), nil
 }
 
 func (w *Wallet) PrivateKey(passphrase, addr string) (string, error) {
@@ -244,8 +375,27 @@ func (w *Wallet) Addresses() map[string]string {
 	return w.store.Addresses()
 }
 
+/// Contains checks if the address belongs to this wallet
+func (w *Wallet) Contains(addrStr string) bool {
+	addr, err := crypto.AddressFromString(addrStr)
+	if err != nil {
+		return false
+	}
+	return w.store.Contains(addr)
+}
+
+/// Label returns the label of the address, or an empty string if the address
+/// doesn't belong to this wallet
+func (w *Wallet) Label(addrStr string) string {
+	return w.store.Label(addrStr)
+}
+
 /// MakeBondTx creates a new bond transaction based on the given parameters
-func (w *Wallet) MakeBondTx(stampStr, seqStr, senderStr, valPubStr, stakeStr, feeStr, memo string) (*tx.Tx, error) {
+/// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeBondTx(stampStr, seqStr, senderStr, valPubStr, stakeStr, feeStr, memo string, force bool) (*tx.Tx, error) {
+	if err := checkMemo(memo); err != nil {
+		return nil, err
+	}
 	sender, err := crypto.AddressFromString(senderStr)
 	if err != nil {
 		return nil, err
@@ -254,7 +404,7 @@ func (w *Wallet) MakeBondTx(stampStr, seqStr, senderStr, valPubStr, stakeStr, fe
 	if err != nil {
 		return nil, err
 	}
-	stake, err := strconv.ParseInt(stakeStr, 10, 64)
+	stake, err := w.ParseAmount(stakeStr)
 	if err != nil {
 		return nil, err
 	}
@@ -271,12 +421,16 @@ func (w *Wallet) MakeBondTx(stampStr, seqStr, senderStr, valPubStr, stakeStr, fe
 		return nil, err
 	}
 
-	tx := tx.NewBondTx(stamp, seq, sender, valPub, stake, fee, memo)
-	return tx, nil
+	trx := tx.NewBondTx(stamp, seq, sender, valPub, int64(stake), fee, memo)
+	return w.preflightCheck(trx, force)
 }
 
 /// MakeUnbondTx creates a new unbond transaction based on the given parameters
-func (w *Wallet) MakeUnbondTx(stampStr, seqStr, addrStr, memo string) (*tx.Tx, error) {
+/// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeUnbondTx(stampStr, seqStr, addrStr, memo string, force bool) (*tx.Tx, error) {
+	if err := checkMemo(memo); err != nil {
+		return nil, err
+	}
 	addr, err := crypto.AddressFromString(addrStr)
 	if err != nil {
 		return nil, err
@@ -290,19 +444,23 @@ func (w *Wallet) MakeUnbondTx(stampStr, seqStr, addrStr, memo string) (*tx.Tx, e
 		return nil, err
 	}
 
-	tx := tx.NewUnbondTx(stamp, seq, addr, memo)
-	return tx, nil
+	trx := tx.NewUnbondTx(stamp, seq, addr, memo)
+	return w.preflightCheck(trx, force)
 }
 
 /// MakeWithdrawTx creates a new unbond transaction based on the given parameters
-func (w *Wallet) MakeWithdrawTx(stampStr, seqStr, valAddrStr, accAddrStr, amountStr, feeStr, memo string) (*tx.Tx, error) {
+/// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeWithdrawTx(stampStr, seqStr, valAddrStr, accAddrStr, amountStr, feeStr, memo string, force bool) (*tx.Tx, error) {
+	if err := checkMemo(memo); err != nil {
+		return nil, err
+	}
 	valAddr, err := crypto.AddressFromString(valAddrStr)
 	if err != nil {
 		return nil, err
 	}
 	accAddr, err := crypto.AddressFromString(accAddrStr)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceiver, err)
 	}
 	stamp, err := w.parsStamp(stampStr)
 	if err != nil {
@@ -312,7 +470,7 @@ func (w *Wallet) MakeWithdrawTx(stampStr, seqStr, valAddrStr, accAddrStr, amount
 	if err != nil {
 		return nil, err
 	}
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	amount, err := w.ParseAmount(amountStr)
 	if err != nil {
 		return nil, err
 	}
@@ -321,21 +479,50 @@ func (w *Wallet) MakeWithdrawTx(stampStr, seqStr, valAddrStr, accAddrStr, amount
 		return nil, err
 	}
 
-	tx := tx.NewWithdrawTx(stamp, seq, valAddr, accAddr, amount, fee, memo)
-	return tx, nil
+	trx := tx.NewWithdrawTx(stamp, seq, valAddr, accAddr, int64(amount), fee, memo)
+	return w.preflightCheck(trx, force)
+}
+
+/// MakeSortitionTx creates a new sortition transaction based on the given parameters.
+/// The stamp should be the stamp of the block that the proof is generated for.
+/// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeSortitionTx(stampStr, seqStr, addrStr, proofStr string, force bool) (*tx.Tx, error) {
+	addr, err := crypto.AddressFromString(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := sortition.ProofFromString(proofStr)
+	if err != nil {
+		return nil, err
+	}
+	stamp, err := w.parsStamp(stampStr)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := w.parsValSeq(addr, seqStr)
+	if err != nil {
+		return nil, err
+	}
+
+	trx := tx.NewSortitionTx(stamp, seq, addr, proof)
+	return w.preflightCheck(trx, force)
 }
 
 /// MakeSendTx creates a new send transaction based on the given parameters
-func (w *Wallet) MakeSendTx(stampStr, seqStr, senderStr, receiverStr, amountStr, feeStr, memo string) (*tx.Tx, error) {
+/// Unless forced, it rejects the transaction if it is invalid for the blockchain state.
+func (w *Wallet) MakeSendTx(stampStr, seqStr, senderStr, receiverStr, amountStr, feeStr, memo string, force bool) (*tx.Tx, error) {
+	if err := checkMemo(memo); err != nil {
+		return nil, err
+	}
 	sender, err := crypto.AddressFromString(senderStr)
 	if err != nil {
 		return nil, err
 	}
 	receiver, err := crypto.AddressFromString(receiverStr)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReceiver, err)
 	}
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	amount, err := w.ParseAmount(amountStr)
 	if err != nil {
 		return nil, err
 	}
@@ -353,8 +540,8 @@ func (w *Wallet) MakeSendTx(stampStr, seqStr, senderStr, receiverStr, amountStr,
 		return nil, err
 	}
 
-	tx := tx.NewSendTx(stamp, seq, sender, receiver, amount, fee, memo)
-	return tx, nil
+	trx := tx.NewSendTx(stamp, seq, sender, receiver, int64(amount), fee, memo)
+	return w.preflightCheck(trx, force)
 }
 
 func (w *Wallet) parsAccSeq(signer crypto.Address, seqStr string) (int32, error) {
@@ -366,23 +553,19 @@ func (w *Wallet) parsAccSeq(signer crypto.Address, seqStr string) (int32, error)
 		return int32(seq), nil
 	}
 
-	return w.client.GetAccountSequence(signer)
+	return w.nextSequence(signer, false)
 }
 
-func (w *Wallet) parsFee(amount int64, feeStr string) (int64, error) {
+func (w *Wallet) parsFee(amount Amount, feeStr string) (int64, error) {
 	if feeStr != "" {
-		fee, err := strconv.ParseInt(feeStr, 10, 64)
+		fee, err := w.ParseAmount(feeStr)
 		if err != nil {
 			return -1, err
 		}
-		return fee, nil
+		return int64(fee), nil
 	}
 
-	fee := amount / 10000
-	if fee < 10000 {
-		fee = 10000
-	}
-	return fee, nil
+	return w.CalcFee(int64(amount)), nil
 }
 
 func (w *Wallet) parsValSeq(signer crypto.Address, seqStr string) (int32, error) {
@@ -394,7 +577,7 @@ func (w *Wallet) parsValSeq(signer crypto.Address, seqStr string) (int32, error)
 		return int32(seq), nil
 	}
 
-	return w.client.GetValidatorSequence(signer)
+	return w.nextSequence(signer, true)
 }
 
 func (w *Wallet) parsStamp(stampStr string) (hash.Stamp, error) {
@@ -408,20 +591,60 @@ func (w *Wallet) parsStamp(stampStr string) (hash.Stamp, error) {
 	return w.client.GetStamp()
 }
 
-func (w *Wallet) SignAndBroadcast(passphrase string, tx *tx.Tx) (string, error) {
-	prv, err := w.store.PrivateKey(passphrase, tx.Payload().Signer().String())
+/// SignTx signs the transaction and returns the signed transaction in bytes.
+/// It refuses to sign if the node is on another chain than the wallet network.
+func (w *Wallet) SignTx(passphrase string, trx *tx.Tx) ([]byte, error) {
+	if err := w.checkChain(); err != nil {
+		return nil, err
+	}
+	prv, err := w.store.PrivateKey(passphrase, trx.Payload().Signer().String())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	signer := crypto.NewSigner(prv)
-	signer.SignMsg(tx)
-	b, err := tx.Bytes()
+	signer.SignMsg(trx)
+	return trx.Bytes()
+}
+
+/// SignAndBroadcast signs and broadcasts the transaction.
+func (w *Wallet) SignAndBroadcast(passphrase string, trx *tx.Tx) (string, error) {
+	_, err := w.SignTx(passphrase, trx)
+	if err != nil {
+		return "", err
+	}
+
+	return w.Broadcast(trx)
+}
+
+/// Broadcast broadcasts the signed transaction.
+/// The transaction is kept as pending, until it is committed or expired.
+func (w *Wallet) Broadcast(trx *tx.Tx) (string, error) {
+	b, err := trx.Bytes()
 	if err != nil {
 		return "", err
 	}
 
-	//fmt.Printf("%x", b)
+	if err := w.checkChain(); err != nil {
+		return "", err
+	}
+	info, err := w.client.GetBlockchainInfo()
+	if err != nil {
+		return "", err
+	}
+	lastHash, err := hash.FromBytes(info.LastBlockHash)
+	if err != nil {
+		return "", err
+	}
+	height := w.stampHeight(trx.Stamp(), info.LastBlockHeight, lastHash)
+	id, err := w.client.SendTx(b)
+	if err != nil {
+		return "", err
+	}
 
-	return w.client.SendTx(b)
+	w.addPending(trx, b, height)
+	if err := w.saveToFile(); err != nil {
+		return id, fmt.Errorf("transaction %s is broadcasted, but it can't be saved as pending: %w", id, err)
+	}
+	return id, nil
 }
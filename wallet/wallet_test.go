@@ -173,3 +173,13 @@ func TestImportPrivateKey(t *testing.T) {
 	// Import again
 	assert.Error(t, tWallet.ImportPrivateKey(tPassphrase, prv1.String()))
 }
+
+func TestContains(t *testing.T) {
+	setup(t)
+
+	for addr, label := range tWallet.Addresses() {
+		assert.True(t, tWallet.Contains(addr))
+		assert.Equal(t, label, tWallet.Label(addr))
+	}
+	assert.False(t, tWallet.Contains("invalid_address"))
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/zarbchain/zarb-wallet/wallet"
+	"github.com/zarbchain/zarb-wallet/www/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	walletpb "github.com/zarbchain/zarb-wallet/www/grpc/proto"
+)
+
+// Server serves the zarb.wallet.v1 service.
+// The clients are authorized by the tokens of the JSON-RPC server, sent in the
+// "authorization: Bearer <token>" metadata. The method names in the tokens are
+// the names of the gRPC methods, like "NewAddress".
+type Server struct {
+	grpc   *grpc.Server
+	tokens []jsonrpc.Token
+}
+
+// NewServer creates a gRPC server for the wallets in the directory of the wallet.
+// The wallet is the default wallet of the requests. The lock guards the wallets,
+// it can be shared with the other servers of the same wallet.
+func NewServer(w *wallet.Wallet, lk sync.Locker, tokens []jsonrpc.Token) *Server {
+	s := &Server{tokens: tokens}
+	s.grpc = grpc.NewServer(grpc.UnaryInterceptor(s.authorize))
+	walletpb.RegisterWalletServer(s.grpc, newWalletServer(w, lk))
+	return s
+}
+
+// Serve serves the requests, it blocks until the server is stopped
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpc.Serve(listener)
+}
+
+// Stop stops the server after the pending requests are finished
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+func (s *Server) authorize(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	auth := md.Get("authorization")
+	if len(auth) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+	token := jsonrpc.FindToken(s.tokens, strings.TrimPrefix(auth[0], "Bearer "))
+	if token == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	method := path.Base(info.FullMethod)
+	if !token.Allows(method) {
+		return nil, status.Errorf(codes.PermissionDenied, "token %s is not allowed to call %s", token.Name, method)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zarbchain/zarb-go/crypto"
+	"github.com/zarbchain/zarb-go/crypto/hash"
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+	"github.com/zarbchain/zarb-wallet/www/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	walletpb "github.com/zarbchain/zarb-wallet/www/grpc/proto"
+)
+
+const (
+	tAdminToken = "admin-token-0123456789"
+	tViewToken  = "view-token-0123456789"
+)
+
+var tWallet *wallet.Wallet
+var tClient walletpb.WalletClient
+
+func setup(t *testing.T) {
+	w, err := wallet.CreateWallet(util.TempFilePath(), "", 0)
+	require.NoError(t, err)
+	_, err = w.NewAddress("", "addr-1")
+	require.NoError(t, err)
+
+	tokens := []jsonrpc.Token{
+		{Name: "admin", Token: tAdminToken, Methods: []string{jsonrpc.AllMethods}},
+		{Name: "view", Token: tViewToken, Methods: []string{"GetWalletInfo", "GetAddresses"}},
+	}
+	s := NewServer(w, &sync.Mutex{}, tokens)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = s.Serve(listener) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	tWallet = w
+	tClient = walletpb.NewWalletClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuthorization(t *testing.T) {
+	setup(t)
+
+	t.Run("Missing token", func(t *testing.T) {
+		_, err := tClient.GetWalletInfo(context.Background(), &walletpb.GetWalletInfoRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		_, err := tClient.GetWalletInfo(withToken("invalid-token-0123456789"), &walletpb.GetWalletInfoRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Not allowed method", func(t *testing.T) {
+		_, err := tClient.NewAddress(withToken(tViewToken), &walletpb.NewAddressRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Allowed method", func(t *testing.T) {
+		res, err := tClient.GetWalletInfo(withToken(tViewToken), &walletpb.GetWalletInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Base(tWallet.Path()), res.WalletName)
+		assert.Equal(t, int32(1), res.AddressCount)
+	})
+}
+
+func TestWallets(t *testing.T) {
+	setup(t)
+	ctx := withToken(tAdminToken)
+
+	t.Run("Invalid wallet name", func(t *testing.T) {
+		_, err := tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{WalletName: "../wallet"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Unknown wallet", func(t *testing.T) {
+		_, err := tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{WalletName: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Not a wallet", func(t *testing.T) {
+		tokensPath := wallet.TokensPath(tWallet.Path())
+		require.NoError(t, os.WriteFile(tokensPath, []byte("[]"), 0600))
+		_, err := tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{WalletName: filepath.Base(tokensPath)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		corrupted := filepath.Join(filepath.Dir(tWallet.Path()), "corrupted")
+		require.NoError(t, os.WriteFile(corrupted, []byte("{invalid"), 0600))
+		_, err = tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{WalletName: "corrupted"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		data, err := os.ReadFile(tWallet.Path())
+		require.NoError(t, err)
+		store := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &store))
+		store["crc"] = 1
+		data, err = json.Marshal(store)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(tWallet.Path()), "bad_crc"), data, 0600))
+		_, err = tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{WalletName: "bad_crc"})
+		assert.Equal(t, codes.DataLoss, status.Code(err))
+
+		// The server is still serving
+		_, err = tClient.GetWalletInfo(ctx, &walletpb.GetWalletInfoRequest{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Wallet exists", func(t *testing.T) {
+		_, err := tClient.CreateWallet(ctx, &walletpb.CreateWalletRequest{})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Create and recover", func(t *testing.T) {
+		name := filepath.Base(util.TempFilePath()) + "_created"
+		created, err := tClient.CreateWallet(ctx, &walletpb.CreateWalletRequest{WalletName: name})
+		require.NoError(t, err)
+		assert.Equal(t, name, created.WalletName)
+
+		_, err = tClient.NewAddress(ctx, &walletpb.NewAddressRequest{WalletName: name, Label: "foo"})
+		require.NoError(t, err)
+		addrs, err := tClient.GetAddresses(ctx, &walletpb.GetAddressesRequest{WalletName: name})
+		require.NoError(t, err)
+		require.Len(t, addrs.Addresses, 1)
+		assert.Equal(t, "foo", addrs.Addresses[0].Label)
+
+		recovered := name + "_recovered"
+		_, err = tClient.RecoverWallet(ctx, &walletpb.RecoverWalletRequest{WalletName: recovered, Mnemonic: created.Mnemonic})
+		require.NoError(t, err)
+		res, err := tClient.NewAddress(ctx, &walletpb.NewAddressRequest{WalletName: recovered})
+		require.NoError(t, err)
+		assert.Equal(t, addrs.Addresses[0].Address, res.Address.Address)
+	})
+}
+
+func TestSignTx(t *testing.T) {
+	setup(t)
+	ctx := withToken(tAdminToken)
+
+	var signer string
+	for addr := range tWallet.Addresses() {
+		signer = addr
+	}
+	sender, _ := crypto.AddressFromString(signer)
+	trx := tx.NewSendTx(hash.GenerateTestStamp(), 1, sender, crypto.GenerateTestAddress(), 1000, 1000, "")
+
+	t.Run("Invalid transaction", func(t *testing.T) {
+		_, err := tClient.SignTx(ctx, &walletpb.SignTxRequest{UnsignedTx: []byte{1, 2, 3}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Chain is not checked", func(t *testing.T) {
+		// The mainnet has no server yet, so the chain can't be checked
+		// and the wallet refuses to sign
+		_, err := tClient.SignTx(ctx, &walletpb.SignTxRequest{UnsignedTx: trx.SignBytes()})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
@@ -0,0 +1,400 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-wallet/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	walletpb "github.com/zarbchain/zarb-wallet/www/grpc/proto"
+)
+
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{wallet.ErrWalletExits, codes.AlreadyExists},
+	{wallet.ErrAddressExists, codes.AlreadyExists},
+	{wallet.ErrInvalidPassphrase, codes.PermissionDenied},
+	{wallet.ErrChainMismatch, codes.FailedPrecondition},
+	{wallet.ErrAddressNotFound, codes.NotFound},
+	{wallet.ErrAccountNotFound, codes.NotFound},
+	{wallet.ErrValidatorNotFound, codes.NotFound},
+	{wallet.ErrInsufficientFunds, codes.FailedPrecondition},
+	{wallet.ErrInvalidSequence, codes.FailedPrecondition},
+	{wallet.ErrInvalidFee, codes.FailedPrecondition},
+	{wallet.ErrValidatorUnbonded, codes.FailedPrecondition},
+	{wallet.ErrValidatorNotUnbonded, codes.FailedPrecondition},
+	{wallet.ErrValidatorNotBonded, codes.FailedPrecondition},
+	{wallet.ErrInvalidAmount, codes.InvalidArgument},
+	{wallet.ErrInvalidReceiver, codes.InvalidArgument},
+	{wallet.ErrMemoTooLong, codes.InvalidArgument},
+	{wallet.ErrNotValidatorAddress, codes.InvalidArgument},
+	{wallet.ErrNotWallet, codes.InvalidArgument},
+	{wallet.ErrInvalidCRC, codes.DataLoss},
+}
+
+// toStatus converts the wallet errors to gRPC status errors.
+// The errors of the node are already status errors.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return status.Error(c.code, err.Error())
+		}
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// walletServer implements the wallet service
+type walletServer struct {
+	lk sync.Locker
+
+	manager     *wallet.Manager
+	defaultName string
+	wallets     map[string]*wallet.Wallet
+}
+
+func newWalletServer(w *wallet.Wallet, lk sync.Locker) *walletServer {
+	return &walletServer{
+		lk:          lk,
+		manager:     wallet.NewManager(filepath.Dir(w.Path())),
+		defaultName: filepath.Base(w.Path()),
+		wallets:     map[string]*wallet.Wallet{w.Path(): w},
+	}
+}
+
+// walletPath returns the path of the wallet, the name should be a file name in the wallets directory
+func (s *walletServer) walletPath(name string) (string, error) {
+	if name == "" {
+		name = s.defaultName
+	}
+	path, err := s.manager.Path(name)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return path, nil
+}
+
+// network returns the network of the default wallet, the new wallets are created on it
+func (s *walletServer) network() int {
+	path, _ := s.walletPath("")
+	return int(s.wallets[path].Network())
+}
+
+// openWallet returns the wallet with the given name, the wallets are opened once.
+// The caller should hold the lock.
+func (s *walletServer) openWallet(name string) (*wallet.Wallet, error) {
+	path, err := s.walletPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if w, ok := s.wallets[path]; ok {
+		return w, nil
+	}
+	// OpenWallet exits the process if the file is not a valid wallet,
+	// so the file is checked first
+	if _, err := s.manager.Info(filepath.Base(path)); err != nil {
+		if errors.Is(err, wallet.ErrWalletNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, toStatus(err)
+	}
+	w, err := wallet.OpenWallet(path)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	s.wallets[path] = w
+	return w, nil
+}
+
+// formatAmount formats the amount in base units for the wallet methods
+func formatAmount(w *wallet.Wallet, amount int64) string {
+	return w.FormatAmount(wallet.Amount(amount))
+}
+
+// formatFee formats the fee, zero means the wallet sets the fee
+func formatFee(w *wallet.Wallet, fee int64) string {
+	if fee == 0 {
+		return ""
+	}
+	return formatAmount(w, fee)
+}
+
+func formatSequence(seq int32) string {
+	if seq == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(seq))
+}
+
+func transactionInfo(trx *tx.Tx) *walletpb.TransactionInfo {
+	return &walletpb.TransactionInfo{
+		Id:       trx.ID().String(),
+		Type:     trx.Payload().Type().String(),
+		Signer:   trx.Payload().Signer().String(),
+		Stamp:    trx.Stamp().String(),
+		Sequence: trx.Sequence(),
+		Amount:   trx.Payload().Value(),
+		Fee:      trx.Fee(),
+		Memo:     trx.Memo(),
+	}
+}
+
+func makeTxResponse(trx *tx.Tx, err error) (*walletpb.MakeTxResponse, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.MakeTxResponse{
+		Transaction: transactionInfo(trx),
+		UnsignedTx:  trx.SignBytes(),
+	}, nil
+}
+
+func (s *walletServer) CreateWallet(_ context.Context, req *walletpb.CreateWalletRequest) (*walletpb.CreateWalletResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	path, err := s.walletPath(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	w, err := wallet.CreateWallet(path, req.Passphrase, s.network())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	mnemonic, err := w.Mnemonic(req.Passphrase)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.CreateWalletResponse{
+		WalletName: filepath.Base(path),
+		Mnemonic:   mnemonic,
+	}, nil
+}
+
+func (s *walletServer) RecoverWallet(_ context.Context, req *walletpb.RecoverWalletRequest) (*walletpb.RecoverWalletResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	path, err := s.walletPath(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := wallet.RecoverWallet(path, req.Mnemonic, s.network()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.RecoverWalletResponse{WalletName: filepath.Base(path)}, nil
+}
+
+func (s *walletServer) GetWalletInfo(_ context.Context, req *walletpb.GetWalletInfoRequest) (*walletpb.GetWalletInfoResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.GetWalletInfoResponse{
+		WalletName:   filepath.Base(w.Path()),
+		Encrypted:    w.IsEncrypted(),
+		AddressCount: int32(len(w.Addresses())),
+	}, nil
+}
+
+func (s *walletServer) GetAddresses(_ context.Context, req *walletpb.GetAddressesRequest) (*walletpb.GetAddressesResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	res := &walletpb.GetAddressesResponse{}
+	for addr, label := range w.Addresses() {
+		res.Addresses = append(res.Addresses, &walletpb.AddressInfo{
+			Address: addr,
+			Label:   label,
+			Role:    w.Role(addr),
+		})
+	}
+	sort.Slice(res.Addresses, func(i, j int) bool {
+		return res.Addresses[i].Address < res.Addresses[j].Address
+	})
+	return res, nil
+}
+
+func (s *walletServer) NewAddress(_ context.Context, req *walletpb.NewAddressRequest) (*walletpb.NewAddressResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.NewAddress(req.Passphrase, req.Label)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.NewAddressResponse{
+		Address: &walletpb.AddressInfo{Address: addr, Label: req.Label, Role: w.Role(addr)},
+	}, nil
+}
+
+func (s *walletServer) GetBalance(_ context.Context, req *walletpb.GetBalanceRequest) (*walletpb.GetBalanceResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	balance, stake, err := w.GetBalance(req.Address)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.GetBalanceResponse{Balance: int64(balance), Stake: int64(stake)}, nil
+}
+
+func (s *walletServer) MakeSendTx(_ context.Context, req *walletpb.MakeSendTxRequest) (*walletpb.MakeTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	opts := req.GetOptions()
+	if req.SendAll {
+		if opts.GetFee() != 0 {
+			return nil, status.Error(codes.InvalidArgument, "fee can't be set when sending all the balance")
+		}
+		return makeTxResponse(w.MakeSweepTx(opts.GetStamp(), formatSequence(opts.GetSequence()),
+			req.From, req.To, opts.GetMemo(), opts.GetForce()))
+	}
+	return makeTxResponse(w.MakeSendTx(opts.GetStamp(), formatSequence(opts.GetSequence()),
+		req.From, req.To, formatAmount(w, req.Amount), formatFee(w, opts.GetFee()), opts.GetMemo(), opts.GetForce()))
+}
+
+func (s *walletServer) MakeBondTx(_ context.Context, req *walletpb.MakeBondTxRequest) (*walletpb.MakeTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	opts := req.GetOptions()
+	return makeTxResponse(w.MakeBondTx(opts.GetStamp(), formatSequence(opts.GetSequence()), req.From,
+		req.ValidatorPublicKey, formatAmount(w, req.Stake), formatFee(w, opts.GetFee()), opts.GetMemo(), opts.GetForce()))
+}
+
+func (s *walletServer) MakeUnbondTx(_ context.Context, req *walletpb.MakeUnbondTxRequest) (*walletpb.MakeTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	opts := req.GetOptions()
+	return makeTxResponse(w.MakeUnbondTx(opts.GetStamp(), formatSequence(opts.GetSequence()),
+		req.Validator, opts.GetMemo(), opts.GetForce()))
+}
+
+func (s *walletServer) MakeWithdrawTx(_ context.Context, req *walletpb.MakeWithdrawTxRequest) (*walletpb.MakeTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	opts := req.GetOptions()
+	return makeTxResponse(w.MakeWithdrawTx(opts.GetStamp(), formatSequence(opts.GetSequence()), req.Validator,
+		req.To, formatAmount(w, req.Amount), formatFee(w, opts.GetFee()), opts.GetMemo(), opts.GetForce()))
+}
+
+func (s *walletServer) SignTx(_ context.Context, req *walletpb.SignTxRequest) (*walletpb.SignTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	trx := new(tx.Tx)
+	if err := trx.DecodeWithNoSignatory(bytes.NewReader(req.UnsignedTx)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	signed, err := w.SignTx(req.Passphrase, trx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.SignTxResponse{
+		Transaction: transactionInfo(trx),
+		SignedTx:    signed,
+	}, nil
+}
+
+func (s *walletServer) BroadcastTx(_ context.Context, req *walletpb.BroadcastTxRequest) (*walletpb.BroadcastTxResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	trx, err := tx.FromBytes(req.SignedTx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := trx.SanityCheck(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	id, err := w.Broadcast(trx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &walletpb.BroadcastTxResponse{Id: id}, nil
+}
+
+func (s *walletServer) GetTransaction(_ context.Context, req *walletpb.GetTransactionRequest) (*walletpb.GetTransactionResponse, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	w, err := s.openWallet(req.WalletName)
+	if err != nil {
+		return nil, err
+	}
+	trx, err := w.GetTransaction(req.Id)
+	if err == nil {
+		return &walletpb.GetTransactionResponse{
+			Status:      walletpb.GetTransactionResponse_CONFIRMED,
+			Transaction: transactionInfo(trx),
+		}, nil
+	}
+	if !errors.Is(err, wallet.ErrTxNotFound) {
+		return nil, toStatus(err)
+	}
+	pending, err := w.PendingTxs()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	for _, p := range pending {
+		if p.ID == req.Id && !p.Expired {
+			return &walletpb.GetTransactionResponse{Status: walletpb.GetTransactionResponse_PENDING}, nil
+		}
+	}
+	return &walletpb.GetTransactionResponse{Status: walletpb.GetTransactionResponse_NOT_FOUND}, nil
+}
@@ -0,0 +1,436 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zarbchain/zarb-go/tx"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+type handler func(s *Server, params json.RawMessage) (interface{}, error)
+
+// methods are the wallet operations that the server exposes.
+// The amounts are in base units, both in the parameters and in the results.
+// The amounts in the parameters are strings of integers, like "1500000000".
+var methods = map[string]handler{
+	"status":         status,
+	"unlock":         unlock,
+	"lock":           lock,
+	"getAddresses":   getAddresses,
+	"newAddress":     newAddress,
+	"getBalance":     getBalance,
+	"makeSendTx":     makeSendTx,
+	"makeBondTx":     makeBondTx,
+	"makeUnbondTx":   makeUnbondTx,
+	"makeWithdrawTx": makeWithdrawTx,
+	"signTx":         signTx,
+	"broadcastTx":    broadcastTx,
+	"getTransaction": getTransaction,
+	"getPendingTxs":  getPendingTxs,
+}
+
+// Methods returns the name of the methods that the server exposes
+func Methods() []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// txParams are the common parameters of making a transaction
+type txParams struct {
+	Stamp    string `json:"stamp"`
+	Sequence int32  `json:"sequence"`
+	Fee      string `json:"fee"`
+	Memo     string `json:"memo"`
+	Force    bool   `json:"force"`
+}
+
+// rawAmount converts an amount in base units to the units of the wallet methods.
+// An empty amount is kept empty, e.g. the wallet calculates the fee if it is not set.
+func rawAmount(w *wallet.Wallet, str string) (string, error) {
+	if str == "" {
+		return "", nil
+	}
+	amt, err := wallet.ParseRawAmount(str)
+	if err != nil {
+		return "", &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return w.FormatAmount(amt), nil
+}
+
+// amounts converts the amounts in base units to the units of the wallet methods
+func amounts(w *wallet.Wallet, strs ...*string) error {
+	for _, str := range strs {
+		amt, err := rawAmount(w, *str)
+		if err != nil {
+			return err
+		}
+		*str = amt
+	}
+	return nil
+}
+
+func (p txParams) seq() string {
+	if p.Sequence == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(p.Sequence))
+}
+
+type txResult struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Signer   string `json:"signer"`
+	Stamp    string `json:"stamp"`
+	Sequence int32  `json:"sequence"`
+	Amount   int64  `json:"amount"`
+	Fee      int64  `json:"fee"`
+	Memo     string `json:"memo"`
+	Signed   bool   `json:"signed"`
+	// Raw is the transaction in hex, without the signature if it is not signed
+	Raw string `json:"raw"`
+}
+
+func makeTxResult(trx *tx.Tx) (*txResult, error) {
+	res := &txResult{
+		ID:       trx.ID().String(),
+		Type:     trx.Payload().Type().String(),
+		Signer:   trx.Payload().Signer().String(),
+		Stamp:    trx.Stamp().String(),
+		Sequence: trx.Sequence(),
+		Amount:   trx.Payload().Value(),
+		Fee:      trx.Fee(),
+		Memo:     trx.Memo(),
+		Signed:   trx.Signature() != nil,
+	}
+	if res.Signed {
+		bs, err := trx.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		res.Raw = hex.EncodeToString(bs)
+	} else {
+		res.Raw = hex.EncodeToString(trx.SignBytes())
+	}
+	return res, nil
+}
+
+type statusResult struct {
+	Path      string     `json:"path"`
+	Network   string     `json:"network"`
+	Encrypted bool       `json:"encrypted"`
+	Locked    bool       `json:"locked"`
+	LockAt    *time.Time `json:"lock_at,omitempty"`
+}
+
+func status(s *Server, _ json.RawMessage) (interface{}, error) {
+	_, err := s.unlockedPassphrase()
+	res := statusResult{
+		Path:      s.wallet.Path(),
+		Network:   s.wallet.Network().String(),
+		Encrypted: s.wallet.IsEncrypted(),
+		Locked:    err != nil,
+	}
+	if !res.Locked && !s.lockAt.IsZero() {
+		lockAt := s.lockAt.UTC()
+		res.LockAt = &lockAt
+	}
+	return res, nil
+}
+
+func unlock(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		Passphrase string `json:"passphrase"`
+		// Timeout is the number of seconds that the wallet remains unlocked,
+		// zero means until it is locked
+		Timeout int `json:"timeout"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Timeout < 0 {
+		return nil, &Error{Code: CodeInvalidParams, Message: "timeout should not be negative"}
+	}
+	if s.wallet.IsEncrypted() {
+		if err := s.unlock(p.Passphrase, time.Duration(p.Timeout)*time.Second); err != nil {
+			return nil, err
+		}
+	}
+	return status(s, nil)
+}
+
+func lock(s *Server, _ json.RawMessage) (interface{}, error) {
+	s.lock()
+	return status(s, nil)
+}
+
+type addressInfo struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+	Role    string `json:"role"`
+}
+
+func getAddresses(s *Server, _ json.RawMessage) (interface{}, error) {
+	addrs := []addressInfo{}
+	for addr, label := range s.wallet.Addresses() {
+		addrs = append(addrs, addressInfo{Address: addr, Label: label, Role: s.wallet.Role(addr)})
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Address < addrs[j].Address })
+	return addrs, nil
+}
+
+func newAddress(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		Label string `json:"label"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	passphrase, err := s.unlockedPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := s.wallet.NewAddress(passphrase, p.Label)
+	if err != nil {
+		return nil, err
+	}
+	return addressInfo{Address: addr, Label: p.Label, Role: s.wallet.Role(addr)}, nil
+}
+
+func getBalance(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		Address string `json:"address"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	balance, stake, err := s.wallet.GetBalance(p.Address)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Address string `json:"address"`
+		Balance int64  `json:"balance"`
+		Stake   int64  `json:"stake"`
+	}{p.Address, int64(balance), int64(stake)}, nil
+}
+
+func makeSendTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		txParams
+		From string `json:"from"`
+		To   string `json:"to"`
+		// Amount can be "all" to send the whole balance
+		Amount string `json:"amount"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	var trx *tx.Tx
+	var err error
+	if p.Amount == "all" {
+		if p.Fee != "" {
+			return nil, &Error{Code: CodeInvalidParams, Message: "fee can't be set when sending all the balance"}
+		}
+		trx, err = s.wallet.MakeSweepTx(p.Stamp, p.seq(), p.From, p.To, p.Memo, p.Force)
+	} else {
+		if err := amounts(s.wallet, &p.Amount, &p.Fee); err != nil {
+			return nil, err
+		}
+		trx, err = s.wallet.MakeSendTx(p.Stamp, p.seq(), p.From, p.To, p.Amount, p.Fee, p.Memo, p.Force)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+func makeBondTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		txParams
+		From      string `json:"from"`
+		PublicKey string `json:"validator_public_key"`
+		Stake     string `json:"stake"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := amounts(s.wallet, &p.Stake, &p.Fee); err != nil {
+		return nil, err
+	}
+	trx, err := s.wallet.MakeBondTx(p.Stamp, p.seq(), p.From, p.PublicKey, p.Stake, p.Fee, p.Memo, p.Force)
+	if err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+func makeUnbondTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		txParams
+		Validator string `json:"validator"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	trx, err := s.wallet.MakeUnbondTx(p.Stamp, p.seq(), p.Validator, p.Memo, p.Force)
+	if err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+func makeWithdrawTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		txParams
+		Validator string `json:"validator"`
+		To        string `json:"to"`
+		Amount    string `json:"amount"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := amounts(s.wallet, &p.Amount, &p.Fee); err != nil {
+		return nil, err
+	}
+	trx, err := s.wallet.MakeWithdrawTx(p.Stamp, p.seq(), p.Validator, p.To, p.Amount, p.Fee, p.Memo, p.Force)
+	if err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+type rawTxParams struct {
+	Raw string `json:"raw"`
+}
+
+func signTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := rawTxParams{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	trx, err := wallet.DecodeUnsignedTx([]byte(p.Raw))
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	passphrase, err := s.unlockedPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.wallet.SignTx(passphrase, trx); err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+func broadcastTx(s *Server, params json.RawMessage) (interface{}, error) {
+	p := rawTxParams{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	trx, err := wallet.DecodeTx([]byte(p.Raw))
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	if err := trx.SanityCheck(); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	if _, err := s.wallet.Broadcast(trx); err != nil {
+		return nil, err
+	}
+	return makeTxResult(trx)
+}
+
+// getTransaction returns the status of a transaction: confirmed, pending or not_found.
+// The node has no index of the transactions of an address, so the history of
+// the wallet is made of the pending transactions and the transactions that the
+// client has kept their IDs.
+func getTransaction(s *Server, params json.RawMessage) (interface{}, error) {
+	p := struct {
+		ID string `json:"id"`
+	}{}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	res := struct {
+		Status string    `json:"status"`
+		Tx     *txResult `json:"tx,omitempty"`
+	}{}
+
+	trx, err := s.wallet.GetTransaction(p.ID)
+	if err == nil {
+		res.Status = "confirmed"
+		res.Tx, err = makeTxResult(trx)
+		return res, err
+	}
+	if !errors.Is(err, wallet.ErrTxNotFound) {
+		return nil, err
+	}
+
+	pending, err := s.wallet.PendingTxs()
+	if err != nil {
+		return nil, err
+	}
+	res.Status = "not_found"
+	for _, pt := range pending {
+		if pt.ID == p.ID && !pt.Expired {
+			res.Status = "pending"
+		}
+	}
+	return res, nil
+}
+
+type pendingTxInfo struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Signer       string    `json:"signer"`
+	Sequence     int32     `json:"sequence"`
+	Value        int64     `json:"value"`
+	Height       int32     `json:"height"`
+	ExpiryHeight int32     `json:"expiry_height"`
+	Expired      bool      `json:"expired"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func getPendingTxs(s *Server, _ json.RawMessage) (interface{}, error) {
+	pending, err := s.wallet.PendingTxs()
+	if err != nil {
+		return nil, err
+	}
+	infos := []pendingTxInfo{}
+	for _, p := range pending {
+		infos = append(infos, pendingTxInfo{
+			ID:           p.ID,
+			Type:         p.Type.String(),
+			Signer:       p.Signer,
+			Sequence:     p.Sequence,
+			Value:        p.Value,
+			Height:       p.Height,
+			ExpiryHeight: p.ExpiryHeight(),
+			Expired:      p.Expired,
+			CreatedAt:    p.CreatedAt,
+		})
+	}
+	return infos, nil
+}
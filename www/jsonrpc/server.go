@@ -0,0 +1,321 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+// Error codes, in addition to the codes defined by JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeWalletError    = -32000 // The wallet operation failed
+	CodeUnauthorized   = -32001 // The token is missing or invalid
+	CodePermission     = -32002 // The token is not allowed to call the method
+	CodeLocked         = -32003 // The wallet is locked
+)
+
+// maxRequestSize is the maximum size of a request body
+const maxRequestSize = 1 << 20
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrLocked is returned when the method needs the wallet to be unlocked
+var ErrLocked = &Error{Code: CodeLocked, Message: "wallet is locked"}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Server serves the wallet operations over JSON-RPC 2.0.
+// The wallet is locked at start, the methods that need the private keys
+// can be called after unlocking it.
+type Server struct {
+	lk sync.Mutex
+
+	wallet     *wallet.Wallet
+	tokens     []Token
+	passphrase string
+	unlocked   bool
+	lockAt     time.Time // Zero means the wallet remains unlocked until it is locked
+	lockTimer  *time.Timer
+	now        func() time.Time
+	afterFunc  func(d time.Duration, f func()) *time.Timer
+	httpServer *http.Server
+}
+
+// NewServer creates a JSON-RPC server for the wallet
+func NewServer(w *wallet.Wallet, tokens []Token) *Server {
+	s := &Server{
+		wallet:    w,
+		tokens:    tokens,
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+	}
+	s.httpServer = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Listen listens on a Unix socket if the address is a path,
+// otherwise on a TCP address that should be a loopback address.
+func Listen(address string) (net.Listener, error) {
+	if strings.Contains(address, "/") {
+		return listenUnix(address)
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return nil, fmt.Errorf("%s is not a loopback address, the server only serves the local clients", host)
+		}
+	}
+	return net.Listen("tcp", address)
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and it is not a socket", path)
+		}
+		// Stale socket of a previous run
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Locker returns the lock that guards the wallet, for sharing the wallet with the other servers
+func (s *Server) Locker() sync.Locker {
+	return &s.lk
+}
+
+// Serve serves the requests, it blocks until the server is shut down
+func (s *Server) Serve(listener net.Listener) error {
+	err := s.httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops the server and locks the wallet
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.lk.Lock()
+	s.lock()
+	s.lk.Unlock()
+	return err
+}
+
+// ServeHTTP handles a JSON-RPC request, or a batch of requests
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := FindToken(s.tokens, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if token == nil {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(rw, http.StatusUnauthorized, response{
+			JSONRPC: "2.0",
+			Error:   &Error{Code: CodeUnauthorized, Message: "invalid token"},
+			ID:      json.RawMessage("null"),
+		})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(rw, http.StatusOK, errorResponse(nil, CodeParseError, err.Error()))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		reqs := []json.RawMessage{}
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			writeJSON(rw, http.StatusOK, errorResponse(nil, CodeParseError, err.Error()))
+			return
+		}
+		if len(reqs) == 0 {
+			writeJSON(rw, http.StatusOK, errorResponse(nil, CodeInvalidRequest, "empty batch"))
+			return
+		}
+		resps := []*response{}
+		for _, data := range reqs {
+			if resp := s.handle(token, data); resp != nil {
+				resps = append(resps, resp)
+			}
+		}
+		if len(resps) == 0 {
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(rw, http.StatusOK, resps)
+		return
+	}
+
+	if !json.Valid(body) {
+		writeJSON(rw, http.StatusOK, errorResponse(nil, CodeParseError, "invalid JSON"))
+		return
+	}
+	resp := s.handle(token, body)
+	if resp == nil {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(rw, http.StatusOK, resp)
+}
+
+// handle handles a request, it returns nil for the notifications
+func (s *Server) handle(token *Token, data []byte) *response {
+	req := request{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(nil, CodeInvalidRequest, err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, CodeInvalidRequest, "invalid request")
+	}
+
+	result, err := s.call(token, req.Method, req.Params)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if err != nil {
+		rpcErr := &Error{}
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeWalletError, Message: err.Error()}
+		}
+		return &response{JSONRPC: "2.0", Error: rpcErr, ID: req.ID}
+	}
+	return &response{JSONRPC: "2.0", Result: result, ID: req.ID}
+}
+
+func (s *Server) call(token *Token, method string, params json.RawMessage) (interface{}, error) {
+	handler, ok := methods[method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", method)}
+	}
+	if !token.Allows(method) {
+		return nil, &Error{Code: CodePermission, Message: fmt.Sprintf("token %s is not allowed to call %s", token.Name, method)}
+	}
+
+	// The wallet is not safe for concurrent use
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	return handler(s, params)
+}
+
+// unlockedPassphrase returns the passphrase if the wallet is unlocked
+func (s *Server) unlockedPassphrase() (string, error) {
+	if !s.wallet.IsEncrypted() {
+		return "", nil
+	}
+	if s.unlocked && !s.lockAt.IsZero() && !s.now().Before(s.lockAt) {
+		s.lock()
+	}
+	if !s.unlocked {
+		return "", ErrLocked
+	}
+	return s.passphrase, nil
+}
+
+func (s *Server) unlock(passphrase string, timeout time.Duration) error {
+	// Decrypting the seed checks the passphrase
+	if _, err := s.wallet.Mnemonic(passphrase); err != nil {
+		return err
+	}
+	s.lock()
+	s.passphrase = passphrase
+	s.unlocked = true
+	if timeout > 0 {
+		s.lockAt = s.now().Add(timeout)
+
+		// Locking on time, so the passphrase doesn't remain in memory
+		// until the next request
+		var timer *time.Timer
+		timer = s.afterFunc(timeout, func() {
+			s.lk.Lock()
+			defer s.lk.Unlock()
+			if s.lockTimer == timer {
+				s.lock()
+			}
+		})
+		s.lockTimer = timer
+	}
+	return nil
+}
+
+func (s *Server) lock() {
+	if s.lockTimer != nil {
+		s.lockTimer.Stop()
+		s.lockTimer = nil
+	}
+	s.passphrase = ""
+	s.unlocked = false
+	s.lockAt = time.Time{}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", Error: &Error{Code: code, Message: msg}, ID: id}
+}
+
+func writeJSON(rw http.ResponseWriter, status int, obj interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(obj)
+}
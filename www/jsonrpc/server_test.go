@@ -0,0 +1,278 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zarbchain/zarb-go/util"
+	"github.com/zarbchain/zarb-wallet/wallet"
+)
+
+const (
+	tPassphrase = "super_secret_password"
+	tAdminToken = "admin-token-0123456789"
+	tViewToken  = "view-token-0123456789"
+)
+
+func setup(t *testing.T, passphrase string) *Server {
+	w, err := wallet.CreateWallet(util.TempFilePath(), passphrase, 0)
+	require.NoError(t, err)
+	_, err = w.NewAddress(passphrase, "addr-1")
+	require.NoError(t, err)
+
+	tokens := []Token{
+		{Name: "admin", Token: tAdminToken, Methods: []string{AllMethods}},
+		{Name: "view", Token: tViewToken, Methods: []string{"status", "getAddresses"}},
+	}
+	return NewServer(w, tokens)
+}
+
+func post(s *Server, token, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, r)
+	return rw
+}
+
+func callMethod(t *testing.T, s *Server, token, method string, params interface{}) response {
+	p, err := json.Marshal(params)
+	require.NoError(t, err)
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  json.RawMessage(p),
+		"id":      1,
+	})
+	require.NoError(t, err)
+
+	rw := post(s, token, string(body))
+	resp := response{}
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestAuthorization(t *testing.T) {
+	s := setup(t, "")
+
+	t.Run("Missing token", func(t *testing.T) {
+		rw := post(s, "", `{"jsonrpc":"2.0","method":"status","id":1}`)
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		rw := post(s, "invalid-token-0123456789", `{"jsonrpc":"2.0","method":"status","id":1}`)
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+
+	t.Run("Not allowed method", func(t *testing.T) {
+		resp := callMethod(t, s, tViewToken, "newAddress", nil)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, CodePermission, resp.Error.Code)
+	})
+
+	t.Run("Allowed method", func(t *testing.T) {
+		resp := callMethod(t, s, tViewToken, "getAddresses", nil)
+		assert.Nil(t, resp.Error)
+	})
+
+	t.Run("Only POST", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, r)
+		assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	})
+}
+
+func TestInvalidRequests(t *testing.T) {
+	s := setup(t, "")
+
+	t.Run("Parse error", func(t *testing.T) {
+		rw := post(s, tAdminToken, `{"jsonrpc":`)
+		resp := response{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, CodeParseError, resp.Error.Code)
+	})
+
+	t.Run("Invalid version", func(t *testing.T) {
+		rw := post(s, tAdminToken, `{"jsonrpc":"1.0","method":"status","id":1}`)
+		resp := response{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, CodeInvalidRequest, resp.Error.Code)
+	})
+
+	t.Run("Method not found", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "getSeed", nil)
+		assert.Equal(t, CodeMethodNotFound, resp.Error.Code)
+	})
+
+	t.Run("Unknown parameter", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "newAddress", map[string]string{"name": "foo"})
+		assert.Equal(t, CodeInvalidParams, resp.Error.Code)
+	})
+}
+
+func TestBatchAndNotification(t *testing.T) {
+	s := setup(t, "")
+
+	t.Run("Notification", func(t *testing.T) {
+		rw := post(s, tAdminToken, `{"jsonrpc":"2.0","method":"status"}`)
+		assert.Equal(t, http.StatusNoContent, rw.Code)
+		assert.Empty(t, rw.Body.Bytes())
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		rw := post(s, tAdminToken, `[
+			{"jsonrpc":"2.0","method":"status","id":1},
+			{"jsonrpc":"2.0","method":"lock"},
+			{"jsonrpc":"2.0","method":"foo","id":2}]`)
+		resps := []response{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resps))
+		require.Len(t, resps, 2)
+		assert.Nil(t, resps[0].Error)
+		assert.Equal(t, json.RawMessage("1"), resps[0].ID)
+		assert.Equal(t, CodeMethodNotFound, resps[1].Error.Code)
+		assert.Equal(t, json.RawMessage("2"), resps[1].ID)
+	})
+
+	t.Run("Empty batch", func(t *testing.T) {
+		rw := post(s, tAdminToken, `[]`)
+		resp := response{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, CodeInvalidRequest, resp.Error.Code)
+	})
+}
+
+func TestLockAndUnlock(t *testing.T) {
+	s := setup(t, tPassphrase)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	t.Run("Locked at start", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "newAddress", map[string]string{"label": "foo"})
+		assert.Equal(t, CodeLocked, resp.Error.Code)
+	})
+
+	t.Run("Invalid passphrase", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "unlock", map[string]interface{}{"passphrase": "invalid"})
+		assert.Equal(t, CodeWalletError, resp.Error.Code)
+	})
+
+	t.Run("Unlock with timeout", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "unlock",
+			map[string]interface{}{"passphrase": tPassphrase, "timeout": 60})
+		require.Nil(t, resp.Error)
+
+		resp = callMethod(t, s, tAdminToken, "newAddress", map[string]string{"label": "foo"})
+		require.Nil(t, resp.Error)
+		assert.Len(t, s.wallet.Addresses(), 2)
+
+		now = now.Add(61 * time.Second)
+		resp = callMethod(t, s, tAdminToken, "newAddress", map[string]string{"label": "bar"})
+		assert.Equal(t, CodeLocked, resp.Error.Code)
+	})
+
+	t.Run("Locked by the timer", func(t *testing.T) {
+		var lockFunc func()
+		s.afterFunc = func(d time.Duration, f func()) *time.Timer {
+			assert.Equal(t, 60*time.Second, d)
+			lockFunc = f
+			return time.AfterFunc(time.Hour, func() {})
+		}
+		resp := callMethod(t, s, tAdminToken, "unlock",
+			map[string]interface{}{"passphrase": tPassphrase, "timeout": 60})
+		require.Nil(t, resp.Error)
+		assert.NotEmpty(t, s.passphrase)
+
+		// The passphrase is cleared without waiting for the next request
+		lockFunc()
+		assert.Empty(t, s.passphrase)
+		assert.False(t, s.unlocked)
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		resp := callMethod(t, s, tAdminToken, "unlock", map[string]interface{}{"passphrase": tPassphrase})
+		require.Nil(t, resp.Error)
+
+		resp = callMethod(t, s, tAdminToken, "lock", nil)
+		require.Nil(t, resp.Error)
+		assert.Empty(t, s.passphrase)
+
+		resp = callMethod(t, s, tAdminToken, "newAddress", map[string]string{"label": "bar"})
+		assert.Equal(t, CodeLocked, resp.Error.Code)
+	})
+}
+
+func TestGetAddresses(t *testing.T) {
+	s := setup(t, "")
+
+	resp := callMethod(t, s, tViewToken, "getAddresses", nil)
+	require.Nil(t, resp.Error)
+	data, err := json.Marshal(resp.Result)
+	require.NoError(t, err)
+	addrs := []addressInfo{}
+	require.NoError(t, json.Unmarshal(data, &addrs))
+	require.Len(t, addrs, 1)
+	assert.Equal(t, "addr-1", addrs[0].Label)
+}
+
+func TestLoadTokens(t *testing.T) {
+	path := util.TempFilePath()
+
+	tokens, created, err := LoadTokens(path)
+	require.NoError(t, err)
+	assert.True(t, created)
+	require.Len(t, tokens, 1)
+	assert.True(t, tokens[0].Allows("signTx"))
+
+	loaded, created, err := LoadTokens(path)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, tokens, loaded)
+
+	require.NoError(t, util.WriteFile(path, []byte(`[{"name":"short","token":"1234","methods":["*"]}]`)))
+	_, _, err = LoadTokens(path)
+	assert.Error(t, err)
+}
+
+func TestListen(t *testing.T) {
+	_, err := Listen("0.0.0.0:0")
+	assert.Error(t, err)
+
+	_, err = Listen("example.com:9901")
+	assert.Error(t, err)
+
+	l, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	l.Close()
+}
+
+func TestRawAmount(t *testing.T) {
+	s := setup(t, "")
+
+	for _, raw := range []bool{false, true} {
+		s.wallet.SetRawUnits(raw)
+		str, err := rawAmount(s.wallet, "1500000000")
+		require.NoError(t, err)
+		amt, err := s.wallet.ParseAmount(str)
+		require.NoError(t, err)
+		assert.Equal(t, wallet.Amount(1500000000), amt, "the amounts should not depend on the units of the wallet")
+	}
+
+	str, err := rawAmount(s.wallet, "")
+	assert.NoError(t, err)
+	assert.Empty(t, str)
+
+	_, err = rawAmount(s.wallet, "1.5")
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, CodeInvalidParams, rpcErr.Code)
+}
@@ -0,0 +1,90 @@
+package jsonrpc
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zarbchain/zarb-go/util"
+)
+
+// AllMethods allows a token to call all the methods
+const AllMethods = "*"
+
+// Token authorizes a client to call some methods of the server.
+// The client sends it in the "Authorization: Bearer <token>" header.
+type Token struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	// Methods are the methods that the client is allowed to call
+	Methods []string `json:"methods"`
+}
+
+// Allows returns true if the token is allowed to call the method
+func (t *Token) Allows(method string) bool {
+	for _, m := range t.Methods {
+		if m == AllMethods || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateToken generates a random token
+func GenerateToken() string {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bs)
+}
+
+// LoadTokens reads the tokens from the file.
+// If the file doesn't exist, it is created with a token that allows all the methods,
+// and it returns true.
+func LoadTokens(path string) ([]Token, bool, error) {
+	if !util.PathExists(path) {
+		tokens := []Token{{Name: "admin", Token: GenerateToken(), Methods: []string{AllMethods}}}
+		data, err := json.MarshalIndent(tokens, "", "  ")
+		if err != nil {
+			return nil, false, err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, false, err
+		}
+		return tokens, true, nil
+	}
+
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	tokens := []Token{}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, false, fmt.Errorf("invalid token file: %w", err)
+	}
+	for _, t := range tokens {
+		if len(t.Token) < 16 {
+			return nil, false, fmt.Errorf("token %s is too short, it should have at least 16 characters", t.Name)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, false, errors.New("there is no token in the token file")
+	}
+	return tokens, false, nil
+}
+
+// FindToken finds the token, comparing it in constant time
+func FindToken(tokens []Token, token string) *Token {
+	var found *Token
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(tokens[i].Token), []byte(token)) == 1 {
+			found = &tokens[i]
+		}
+	}
+	return found
+}